@@ -0,0 +1,80 @@
+package crazy
+
+import "math"
+
+// Alias selects indices at random with probabilities proportional to fixed
+// weights in constant time, using Vose's alias method. See Vose, "A linear
+// algorithm for generating random numbers with a given distribution," IEEE
+// Trans. Software Eng. 17(9), 1991.
+type Alias struct {
+	Source
+	// prob[i] is the probability of keeping i rather than taking alias[i].
+	prob  []float64
+	alias []int
+}
+
+// NewAlias creates an alias table drawing from the specified source with
+// probabilities proportional to weights. It panics if any weight is negative
+// or not finite, or if the weights sum to zero.
+func NewAlias(src Source, weights []float64) Alias {
+	n := len(weights)
+	var sum float64
+	for _, w := range weights {
+		if !(w >= 0) || math.IsInf(w, 1) {
+			panic("crazy: invalid alias weight")
+		}
+		sum += w
+	}
+	if !(sum > 0) {
+		panic("crazy: alias weights sum to zero")
+	}
+	a := Alias{
+		Source: src,
+		prob:   make([]float64, n),
+		alias:  make([]int, n),
+	}
+	// Scale so that the average weight is 1, then pair each small weight with
+	// a large one that tops it up.
+	var small, large []int
+	for i, w := range weights {
+		a.prob[i] = w * float64(n) / sum
+		if a.prob[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small = small[:len(small)-1]
+		a.alias[s] = l
+		a.prob[l] -= 1 - a.prob[s]
+		if a.prob[l] < 1 {
+			large = large[:len(large)-1]
+			small = append(small, l)
+		}
+	}
+	// Whatever remains is 1 up to rounding.
+	for _, i := range large {
+		a.prob[i] = 1
+	}
+	for _, i := range small {
+		a.prob[i] = 1
+	}
+	return a
+}
+
+// Len returns the number of indices in the table.
+func (a Alias) Len() int {
+	return len(a.prob)
+}
+
+// Int generates a random index in [0, a.Len()).
+func (a Alias) Int() int {
+	i := RNG{a.Source}.Intn(len(a.prob))
+	u := Uniform0_1{a.Source}
+	if u.Next() < a.prob[i] {
+		return i
+	}
+	return a.alias[i]
+}
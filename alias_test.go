@@ -0,0 +1,49 @@
+package crazy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAlias(t *testing.T) {
+	weights := []float64{1, 0, 3, 0.5, 5.5, 10}
+	a := NewAlias(CryptoSeeded(NewXoshiro(), 32), weights)
+	if a.Len() != len(weights) {
+		t.Fatalf("table has length %d, want %d", a.Len(), len(weights))
+	}
+	const n = 100000
+	counts := make([]int, len(weights))
+	for i := 0; i < n; i++ {
+		counts[a.Int()]++
+	}
+	for i, w := range weights {
+		p := w / 20
+		se := math.Sqrt(p * (1 - p) / n)
+		if got := float64(counts[i]) / n; math.Abs(got-p) > 5*se {
+			t.Errorf("index %d has frequency %v, want %v", i, got, p)
+		}
+	}
+	if counts[1] != 0 {
+		t.Errorf("zero-weight index chosen %d times", counts[1])
+	}
+}
+
+func TestAliasInvalid(t *testing.T) {
+	cases := [][]float64{
+		{},
+		{0, 0},
+		{1, -1},
+		{1, math.NaN()},
+		{1, math.Inf(1)},
+	}
+	for _, w := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("no panic with weights %v", w)
+				}
+			}()
+			NewAlias(nil, w)
+		}()
+	}
+}
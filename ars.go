@@ -0,0 +1,218 @@
+package crazy
+
+import (
+	"math"
+	"sort"
+)
+
+// ARS implements adaptive rejection sampling for log-concave densities. The
+// envelope is formed by tangents to the log density at a set of abscissae,
+// and each rejected candidate is added to the set, so the envelope converges
+// to the density as sampling proceeds. See Gilks and Wild, "Adaptive
+// rejection sampling for Gibbs sampling," Applied Statistics 41(2), 1992.
+//
+// Unlike most distributions, ARS changes as it is used, so it must be used
+// through a pointer, and it is not safe for concurrent use.
+type ARS struct {
+	Source
+	// LogPDF is the logarithm of the (possibly unnormalized) density. It must
+	// be concave.
+	LogPDF func(x float64) float64
+	// DLogPDF is the derivative of LogPDF.
+	DLogPDF func(x float64) float64
+	// Lo and Hi bound the support of the density. Either may be infinite.
+	Lo, Hi float64
+	// MaxPoints limits the number of abscissae in the envelope. Once reached,
+	// the envelope is no longer refined.
+	MaxPoints int
+
+	// x are the abscissae in increasing order, with h = LogPDF(x) and
+	// dh = DLogPDF(x).
+	x, h, dh []float64
+	// z[i] is the intersection of the tangents at x[i] and x[i+1].
+	z []float64
+	// c[i] is the cumulative area under the envelope up to z[i], scaled by
+	// exp(-hmax).
+	c    []float64
+	hmax float64
+}
+
+// NewARS creates an adaptive rejection sampler drawing from the specified
+// source. init gives at least two starting abscissae within (lo, hi). If lo is
+// -Inf, the derivative at the smallest abscissa must be positive, and if hi is
+// +Inf, the derivative at the largest must be negative. It panics if these
+// conditions are not met.
+func NewARS(src Source, logpdf, dlogpdf func(x float64) float64, lo, hi float64, init ...float64) *ARS {
+	if len(init) < 2 {
+		panic("crazy: ARS needs at least two initial points")
+	}
+	a := &ARS{
+		Source:    src,
+		LogPDF:    logpdf,
+		DLogPDF:   dlogpdf,
+		Lo:        lo,
+		Hi:        hi,
+		MaxPoints: 64,
+	}
+	x := append([]float64(nil), init...)
+	sort.Float64s(x)
+	for _, v := range x {
+		if !(v > lo && v < hi) {
+			panic("crazy: ARS initial point outside support")
+		}
+		a.x = append(a.x, v)
+		a.h = append(a.h, logpdf(v))
+		a.dh = append(a.dh, dlogpdf(v))
+	}
+	if math.IsInf(lo, -1) && !(a.dh[0] > 0) {
+		panic("crazy: ARS needs positive slope at leftmost point")
+	}
+	if math.IsInf(hi, 1) && !(a.dh[len(a.dh)-1] < 0) {
+		panic("crazy: ARS needs negative slope at rightmost point")
+	}
+	a.build()
+	return a
+}
+
+// Points returns the number of abscissae currently in the envelope.
+func (a *ARS) Points() int {
+	return len(a.x)
+}
+
+// build computes the tangent intersections and envelope areas.
+func (a *ARS) build() {
+	k := len(a.x)
+	a.z = a.z[:0]
+	for i := 0; i < k-1; i++ {
+		d := a.dh[i] - a.dh[i+1]
+		var z float64
+		if d > 1e-12*math.Max(math.Abs(a.dh[i]), math.Abs(a.dh[i+1])) {
+			z = (a.h[i+1] - a.h[i] - a.x[i+1]*a.dh[i+1] + a.x[i]*a.dh[i]) / d
+		}
+		if !(z > a.x[i] && z < a.x[i+1]) {
+			// Nearly parallel tangents or rounding trouble.
+			z = 0.5 * (a.x[i] + a.x[i+1])
+		}
+		a.z = append(a.z, z)
+	}
+	a.hmax = a.h[0]
+	for _, v := range a.h {
+		if v > a.hmax {
+			a.hmax = v
+		}
+	}
+	a.c = a.c[:0]
+	var s float64
+	for i := 0; i < k; i++ {
+		lo, hi := a.seg(i)
+		s += a.area(i, lo, hi)
+		a.c = append(a.c, s)
+	}
+}
+
+// seg returns the bounds of the envelope segment tangent at x[i].
+func (a *ARS) seg(i int) (lo, hi float64) {
+	lo, hi = a.Lo, a.Hi
+	if i > 0 {
+		lo = a.z[i-1]
+	}
+	if i < len(a.z) {
+		hi = a.z[i]
+	}
+	return lo, hi
+}
+
+// area integrates the scaled exponentiated tangent at x[i] over [lo, hi].
+func (a *ARS) area(i int, lo, hi float64) float64 {
+	h, dh, x := a.h[i]-a.hmax, a.dh[i], a.x[i]
+	if dh == 0 {
+		return math.Exp(h) * (hi - lo)
+	}
+	// Integrate from whichever end has the greater envelope to avoid
+	// subtracting infinities.
+	if dh > 0 {
+		return math.Exp(h+dh*(hi-x)) * -math.Expm1(-dh*(hi-lo)) / dh
+	}
+	return math.Exp(h+dh*(lo-x)) * math.Expm1(dh*(hi-lo)) / dh
+}
+
+// upper evaluates the envelope at y, which lies in segment i.
+func (a *ARS) upper(i int, y float64) float64 {
+	return a.h[i] + a.dh[i]*(y-a.x[i])
+}
+
+// lower evaluates the squeezing function at y, which is -Inf outside the
+// abscissae.
+func (a *ARS) lower(y float64) float64 {
+	k := len(a.x)
+	if y < a.x[0] || y > a.x[k-1] {
+		return math.Inf(-1)
+	}
+	j := sort.SearchFloat64s(a.x, y)
+	if j == 0 {
+		return a.h[0]
+	}
+	t := (y - a.x[j-1]) / (a.x[j] - a.x[j-1])
+	return a.h[j-1] + t*(a.h[j]-a.h[j-1])
+}
+
+// insert adds y to the abscissae and rebuilds the envelope.
+func (a *ARS) insert(y, hy, dhy float64) {
+	j := sort.SearchFloat64s(a.x, y)
+	if j < len(a.x) && a.x[j] == y {
+		return
+	}
+	a.x = append(a.x, 0)
+	a.h = append(a.h, 0)
+	a.dh = append(a.dh, 0)
+	copy(a.x[j+1:], a.x[j:])
+	copy(a.h[j+1:], a.h[j:])
+	copy(a.dh[j+1:], a.dh[j:])
+	a.x[j], a.h[j], a.dh[j] = y, hy, dhy
+	a.build()
+}
+
+// Next generates a variate.
+func (a *ARS) Next() float64 {
+	u := Uniform0_1{a.Source}
+	for {
+		// Choose a segment by area, then sample the exponential within it.
+		k := len(a.x)
+		r := u.Next() * a.c[k-1]
+		i := sort.SearchFloat64s(a.c, r)
+		if i >= k {
+			i = k - 1
+		}
+		lo, hi := a.seg(i)
+		dh := a.dh[i]
+		v := u.Next()
+		var y float64
+		switch {
+		case dh == 0:
+			y = lo + v*(hi-lo)
+		case dh > 0:
+			y = hi + math.Log1p(v*math.Expm1(-dh*(hi-lo)))/dh
+		default:
+			y = lo + math.Log1p(v*math.Expm1(dh*(hi-lo)))/dh
+		}
+		if failed(a.Source) {
+			return math.NaN()
+		}
+		if !(y >= lo && y <= hi) {
+			continue
+		}
+		uy := a.upper(i, y)
+		w := math.Log(u.Next())
+		if w <= a.lower(y)-uy {
+			return y
+		}
+		hy := a.LogPDF(y)
+		accept := w <= hy-uy
+		if len(a.x) < a.MaxPoints {
+			a.insert(y, hy, a.DLogPDF(y))
+		}
+		if accept {
+			return y
+		}
+	}
+}
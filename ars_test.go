@@ -0,0 +1,60 @@
+package crazy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestARSNormal(t *testing.T) {
+	d := NewARS(CryptoSeeded(NewMT64(), mt64N),
+		func(x float64) float64 { return -0.5 * x * x },
+		func(x float64) float64 { return -x },
+		math.Inf(-1), math.Inf(1), -1, 1)
+	testMoments(t, knownMoments{d, 0, 1}, 1<<18)
+}
+
+func TestARSGamma(t *testing.T) {
+	// Gamma(3, 1) has mean 3 and variance 3.
+	d := NewARS(CryptoSeeded(NewMT64(), mt64N),
+		func(x float64) float64 { return 2*math.Log(x) - x },
+		func(x float64) float64 { return 2/x - 1 },
+		0, math.Inf(1), 1, 5)
+	testMoments(t, knownMoments{d, 3, 3}, 1<<18)
+	if d.Points() > d.MaxPoints {
+		t.Errorf("envelope has %d points, max %d", d.Points(), d.MaxPoints)
+	}
+}
+
+func TestARSBounded(t *testing.T) {
+	// Truncated exponential on [0, 1].
+	d := NewARS(CryptoSeeded(NewMT64(), mt64N),
+		func(x float64) float64 { return -x },
+		func(x float64) float64 { return -1 },
+		0, 1, 0.25, 0.75)
+	for i := 0; i < 1<<16; i++ {
+		if x := d.Next(); x < 0 || x > 1 {
+			t.Fatalf("variate %g out of range", x)
+		}
+	}
+}
+
+// knownMoments attaches a known mean and variance to a Distribution for
+// testMoments.
+type knownMoments struct {
+	Distribution
+	mean, variance float64
+}
+
+func (d knownMoments) Mean() float64     { return d.mean }
+func (d knownMoments) Variance() float64 { return d.variance }
+
+func BenchmarkARS(b *testing.B) {
+	d := NewARS(CryptoSeeded(NewMT64(), mt64N),
+		func(x float64) float64 { return -0.5 * x * x },
+		func(x float64) float64 { return -x },
+		math.Inf(-1), math.Inf(1), -1, 1)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = d.Next()
+	}
+}
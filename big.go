@@ -0,0 +1,101 @@
+package crazy
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// randBits sets z to a random integer of at most nbits bits, reusing z's
+// storage, and returns z.
+func randBits(src Source, z *big.Int, nbits int) *big.Int {
+	n := (nbits + bits.UintSize - 1) / bits.UintSize
+	w := z.Bits()
+	if cap(w) < n {
+		w = make([]big.Word, n)
+	}
+	w = w[:n]
+	for i := range w {
+		w[i] = big.Word(readUint(src, bits.UintSize/8))
+	}
+	if r := uint(nbits) % bits.UintSize; r != 0 {
+		w[n-1] &= 1<<r - 1
+	}
+	return z.SetBits(w)
+}
+
+// BigRange sets z to a random integer in the interval [lo, hi) and returns z.
+// If z is nil, a new Int is allocated. z may alias lo or hi. Values are drawn
+// by rejection directly into z's storage, so repeated calls with the same z
+// allocate only to compute hi-lo; for many draws from the same range, a
+// BignSampler avoids even that. It panics if hi <= lo.
+func (r RNG) BigRange(z, lo, hi *big.Int) *big.Int {
+	w := new(big.Int).Sub(hi, lo)
+	if w.Sign() <= 0 {
+		panic("empty range")
+	}
+	if z == nil {
+		z = new(big.Int)
+	} else if z == lo {
+		lo = new(big.Int).Set(lo)
+	}
+	nbits := w.BitLen()
+	for randBits(r.Source, z, nbits).Cmp(w) >= 0 {
+		// Rejected; each attempt succeeds with probability above 1/2.
+	}
+	return z.Add(z, lo)
+}
+
+// BigFloat sets z to a random float in [0, 1) with precision prec and returns
+// z. The result is a multiple of 2**-prec, like Uniform0_1 with 53 bits. The
+// random bits are drawn into buf, which is scratch space that may be reused
+// across calls; with the same z and buf each time, BigFloat does not allocate
+// once both have grown to prec bits. If z or buf is nil, a new value is
+// allocated in its place. It panics if prec == 0.
+func (r RNG) BigFloat(z *big.Float, prec uint, buf *big.Int) *big.Float {
+	if prec == 0 {
+		panic("precision zero")
+	}
+	if z == nil {
+		z = new(big.Float)
+	}
+	if buf == nil {
+		buf = new(big.Int)
+	}
+	randBits(r.Source, buf, int(prec))
+	z.SetPrec(prec).SetInt(buf)
+	return z.SetMantExp(z, -int(prec))
+}
+
+// BignSampler generates random integers in [0, max) for a fixed max. The
+// bound and its bit length are computed once, and Next reuses the storage of
+// its argument, so repeated draws do not allocate.
+type BignSampler struct {
+	src   Source
+	max   *big.Int
+	nbits int
+}
+
+// NewBignSampler creates a sampler drawing from src in [0, max). max is
+// copied. It panics if max <= 0.
+func NewBignSampler(src Source, max *big.Int) BignSampler {
+	if max.Sign() <= 0 {
+		panic("maximum zero or below")
+	}
+	return BignSampler{
+		src:   src,
+		max:   new(big.Int).Set(max),
+		nbits: max.BitLen(),
+	}
+}
+
+// Next sets z to a random integer in [0, max) and returns z. If z is nil, a
+// new Int is allocated.
+func (s BignSampler) Next(z *big.Int) *big.Int {
+	if z == nil {
+		z = new(big.Int)
+	}
+	for randBits(s.src, z, s.nbits).Cmp(s.max) >= 0 {
+		// Rejected; each attempt succeeds with probability above 1/2.
+	}
+	return z
+}
@@ -0,0 +1,152 @@
+package crazy
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestBigRange(t *testing.T) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	const n, k = 70000, 7
+	lo, hi := big.NewInt(-3), big.NewInt(4)
+	counts := make([]int, k)
+	z := new(big.Int)
+	for i := 0; i < n; i++ {
+		v := r.BigRange(z, lo, hi)
+		if v != z {
+			t.Fatal("BigRange did not return z")
+		}
+		if v.Cmp(lo) < 0 || v.Cmp(hi) >= 0 {
+			t.Fatalf("BigRange gave %v", v)
+		}
+		counts[v.Int64()+3]++
+	}
+	chi2 := chi2Uniform(counts, k)
+	if chi2 > 28 {
+		t.Errorf("BigRange is not uniform: chi2 = %v, counts %v", chi2, counts)
+	}
+}
+
+func TestBigRangeWide(t *testing.T) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	lo := new(big.Int).Lsh(big.NewInt(1), 200)
+	hi := new(big.Int).Lsh(big.NewInt(3), 200)
+	var upper int
+	for i := 0; i < 1000; i++ {
+		v := r.BigRange(nil, lo, hi)
+		if v.Cmp(lo) < 0 || v.Cmp(hi) >= 0 {
+			t.Fatalf("BigRange gave %v", v)
+		}
+		if v.Bit(201) != 0 {
+			upper++
+		}
+	}
+	if upper < 400 || upper > 600 {
+		t.Errorf("%d of 1000 values in upper half", upper)
+	}
+	// z may alias either bound.
+	a, b := big.NewInt(10), big.NewInt(12)
+	if v := r.BigRange(a, a, b); v.Cmp(big.NewInt(10)) < 0 || v.Cmp(b) >= 0 {
+		t.Errorf("BigRange aliasing lo gave %v", v)
+	}
+	a, b = big.NewInt(10), big.NewInt(12)
+	if v := r.BigRange(b, a, b); v.Cmp(a) < 0 || v.Cmp(big.NewInt(12)) >= 0 {
+		t.Errorf("BigRange aliasing hi gave %v", v)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("empty BigRange did not panic")
+		}
+	}()
+	r.BigRange(nil, hi, lo)
+}
+
+func TestBignSampler(t *testing.T) {
+	src := CryptoSeeded(NewXoshiro(), 32)
+	const n, k = 70000, 7
+	s := NewBignSampler(src, big.NewInt(k))
+	counts := make([]int, k)
+	z := new(big.Int)
+	for i := 0; i < n; i++ {
+		v := s.Next(z)
+		if v.Sign() < 0 || v.Cmp(big.NewInt(k)) >= 0 {
+			t.Fatalf("BignSampler gave %v", v)
+		}
+		counts[v.Int64()]++
+	}
+	chi2 := chi2Uniform(counts, k)
+	if chi2 > 28 {
+		t.Errorf("BignSampler is not uniform: chi2 = %v, counts %v", chi2, counts)
+	}
+	max := new(big.Int).Lsh(big.NewInt(1), 521)
+	max.Sub(max, big.NewInt(1))
+	s = NewBignSampler(src, max)
+	allocs := testing.AllocsPerRun(100, func() { s.Next(z) })
+	if allocs != 0 {
+		t.Errorf("BignSampler.Next allocated %v times per call", allocs)
+	}
+	if z.Cmp(max) >= 0 {
+		t.Errorf("BignSampler gave %v", z)
+	}
+}
+
+func TestBigFloat(t *testing.T) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	const n = 10000
+	z, buf := new(big.Float), new(big.Int)
+	var m float64
+	for i := 0; i < n; i++ {
+		v := r.BigFloat(z, 200, buf)
+		if v != z || v.Prec() != 200 {
+			t.Fatalf("BigFloat returned a different Float or precision %d", v.Prec())
+		}
+		if v.Sign() < 0 || v.Cmp(big.NewFloat(1)) >= 0 {
+			t.Fatalf("BigFloat gave %v", v)
+		}
+		x, _ := v.Float64()
+		m += x
+	}
+	m /= n
+	if math.Abs(m-0.5) > 6*math.Sqrt(1.0/12/n) {
+		t.Errorf("BigFloat sample mean %v", m)
+	}
+	w := words{^uint64(0), ^uint64(0)}
+	v := (RNG{&w}).BigFloat(nil, 70, nil)
+	want := new(big.Float).SetPrec(80).SetInt64(1)
+	want.Sub(big.NewFloat(1), want.SetMantExp(want, -70))
+	if v.Cmp(want) != 0 {
+		t.Errorf("BigFloat of all ones is %v, want %v", v, want)
+	}
+}
+
+func TestBigFloatAllocs(t *testing.T) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	z, buf := new(big.Float), new(big.Int)
+	r.BigFloat(z, 1000, buf)
+	allocs := testing.AllocsPerRun(100, func() { r.BigFloat(z, 1000, buf) })
+	if allocs != 0 {
+		t.Errorf("BigFloat allocated %v times per call", allocs)
+	}
+}
+
+var bigSink *big.Int
+
+func BenchmarkBign(b *testing.B) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	max := new(big.Int).Lsh(big.NewInt(1), 2047)
+	max.Add(max, big.NewInt(12345))
+	for i := 0; i < b.N; i++ {
+		bigSink = r.Bign(max)
+	}
+}
+
+func BenchmarkBignSampler(b *testing.B) {
+	max := new(big.Int).Lsh(big.NewInt(1), 2047)
+	max.Add(max, big.NewInt(12345))
+	s := NewBignSampler(CryptoSeeded(NewXoshiro(), 32), max)
+	z := new(big.Int)
+	for i := 0; i < b.N; i++ {
+		bigSink = s.Next(z)
+	}
+}
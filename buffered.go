@@ -0,0 +1,251 @@
+package crazy
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// ErrNotSaver is returned when saving or restoring a wrapper whose underlying
+// Source is not a Saver.
+var ErrNotSaver = errors.New("crazy: source is not a Saver")
+
+// BufferedRNG is a Source that reads large blocks from an underlying Source
+// and serves integers and floats from the buffer, amortizing the cost of
+// calling Read through an interface for every value. The bytes produced by a
+// BufferedRNG are those of the underlying Source read in whole blocks, in
+// order, regardless of how they are requested.
+//
+// The bit-level methods Bits, Bool, Bernoulli, and BernoulliRat draw whole
+// 64-bit words from the same stream and keep the bits they do not use for
+// later calls, so that a coin flip costs one bit rather than one word.
+//
+// Methods other than Read have no error result, so BufferedRNG records the
+// first error from the underlying Source for Err to report. To stop producing
+// values after an error, have the BufferedRNG read from a CheckedRNG.
+//
+// BufferedRNG holds state, so it must be used through a pointer, and it is not
+// safe for concurrent use.
+type BufferedRNG struct {
+	src Source
+	buf []byte
+	off int
+	err error
+	// bits holds nbits leftover random bits, consumed from the low end.
+	bits  uint64
+	nbits uint
+}
+
+// NewBufferedRNG creates a buffered RNG reading from src in blocks of size
+// bytes, rounded up to a multiple of 8. If size <= 0, 4096 is used.
+func NewBufferedRNG(src Source, size int) *BufferedRNG {
+	if size <= 0 {
+		size = 4096
+	}
+	size = (size + 7) &^ 7
+	return &BufferedRNG{src: src, buf: make([]byte, size), off: size}
+}
+
+// Read fills p with bytes from the buffer, refilling it from the underlying
+// Source as needed. Short reads from the underlying Source are retried until
+// the buffer is full. If the Source fails, the bytes it did produce are still
+// served, and the returned error is that of the Source.
+func (b *BufferedRNG) Read(p []byte) (n int, err error) {
+	for n < len(p) {
+		if b.off == len(b.buf) {
+			err = b.fill()
+		}
+		k := copy(p[n:], b.buf[b.off:])
+		b.off += k
+		n += k
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// fill refills the empty buffer from the underlying Source. If the Source
+// fails, the bytes read before the error are moved to the end of the buffer
+// so that only they remain buffered, and the error is recorded.
+func (b *BufferedRNG) fill() error {
+	k, err := io.ReadFull(b.src, b.buf)
+	if err != nil {
+		if b.err == nil {
+			b.err = err
+		}
+		copy(b.buf[len(b.buf)-k:], b.buf[:k])
+	}
+	b.off = len(b.buf) - k
+	return err
+}
+
+// Err returns the first error from the underlying Source, including errors
+// encountered by methods that cannot return them, such as Uint64.
+func (b *BufferedRNG) Err() error {
+	return b.err
+}
+
+// Uint64 generates a random uint64.
+func (b *BufferedRNG) Uint64() uint64 {
+	return b.next(8)
+}
+
+// Uint32 generates a random uint32.
+func (b *BufferedRNG) Uint32() uint32 {
+	return uint32(b.next(4))
+}
+
+// Uint16 generates a random uint16.
+func (b *BufferedRNG) Uint16() uint16 {
+	return uint16(b.next(2))
+}
+
+// next consumes n <= 8 bytes and returns them as a little-endian integer. If
+// the underlying Source fails, bytes it could not produce are zero, and the
+// error is recorded for Err.
+func (b *BufferedRNG) next(n int) uint64 {
+	if len(b.buf)-b.off < 8 {
+		var p [8]byte
+		b.Read(p[:n])
+		return binary.LittleEndian.Uint64(p[:])
+	}
+	v := binary.LittleEndian.Uint64(b.buf[b.off:]) & (^uint64(0) >> uint(64-8*n))
+	b.off += n
+	return v
+}
+
+// Float64 generates a uniform variate in [0, 1) with 53 bits of precision,
+// the same as Uniform0_1.
+func (b *BufferedRNG) Float64() float64 {
+	return float64(b.Uint64()&0x1fffffffffffff) * 1.11022302462515654042e-16
+}
+
+// Bits generates a random integer with n bits, taken from the bits left over
+// by previous calls before drawing another word. It panics if n > 64.
+func (b *BufferedRNG) Bits(n uint) uint64 {
+	if n > 64 {
+		panic("bit count above 64")
+	}
+	if n == 0 {
+		return 0
+	}
+	if n <= b.nbits {
+		v := b.bits & mask(n)
+		b.bits >>= n
+		b.nbits -= n
+		return v
+	}
+	// Use all leftover bits, then the rest from a new word.
+	k := b.nbits
+	m := n - k
+	w := b.next(8)
+	v := b.bits | (w&mask(m))<<k
+	b.bits = w >> m
+	b.nbits = 64 - m
+	return v
+}
+
+// mask returns a mask of the low n bits, 0 < n <= 64.
+func mask(n uint) uint64 {
+	return ^uint64(0) >> (64 - n)
+}
+
+// Bool generates a random bool using a single bit.
+func (b *BufferedRNG) Bool() bool {
+	return b.Bits(1) != 0
+}
+
+// Bernoulli returns true with probability p, using two bits on average. See
+// RNG.Bernoulli.
+func (b *BufferedRNG) Bernoulli(p float64) bool {
+	return RNG{b}.Bernoulli(p)
+}
+
+// BernoulliRat returns true with probability exactly num/den, using two bits on
+// average. See RNG.BernoulliRat.
+func (b *BufferedRNG) BernoulliRat(num, den uint64) bool {
+	return RNG{b}.BernoulliRat(num, den)
+}
+
+// Buffered returns the number of bytes remaining in the buffer.
+func (b *BufferedRNG) Buffered() int {
+	return len(b.buf) - b.off
+}
+
+// SeedIV seeds the underlying Source, discards the buffer, and clears the
+// recorded error. It panics if the underlying Source is not a Seeder.
+func (b *BufferedRNG) SeedIV(iv []byte) {
+	s, ok := b.src.(Seeder)
+	if !ok {
+		panic("crazy: source is not a Seeder")
+	}
+	s.SeedIV(iv)
+	b.off = len(b.buf)
+	b.err = nil
+	b.bits, b.nbits = 0, 0
+}
+
+// Save writes the state of the underlying Source followed by the remaining
+// buffered bytes and the leftover bits of the bit-level methods, so that a
+// BufferedRNG restoring it produces exactly the same values as this one. It
+// returns ErrNotSaver if the underlying Source is not a Saver.
+func (b *BufferedRNG) Save(into io.Writer) (n int, err error) {
+	s, ok := b.src.(Saver)
+	if !ok {
+		return 0, ErrNotSaver
+	}
+	if n, err = s.Save(into); err != nil {
+		return n, err
+	}
+	rem := b.buf[b.off:]
+	p := make([]byte, 4+len(rem)+9)
+	binary.LittleEndian.PutUint32(p, uint32(len(rem)))
+	copy(p[4:], rem)
+	binary.LittleEndian.PutUint64(p[4+len(rem):], b.bits)
+	p[len(p)-1] = byte(b.nbits)
+	k, err := into.Write(p)
+	return n + k, err
+}
+
+// Restore loads a state written by Save. The buffer size must be at least the
+// number of bytes that were buffered when the state was saved. It returns
+// ErrNotSaver if the underlying Source is not a Saver.
+func (b *BufferedRNG) Restore(from io.Reader) (n int, err error) {
+	s, ok := b.src.(Saver)
+	if !ok {
+		return 0, ErrNotSaver
+	}
+	if n, err = s.Restore(from); err != nil {
+		return n, err
+	}
+	var p [4]byte
+	k, err := io.ReadFull(from, p[:])
+	n += k
+	if err != nil {
+		return n, err
+	}
+	r := int(binary.LittleEndian.Uint32(p[:]))
+	if r > len(b.buf) {
+		return n, errors.New("crazy: saved buffer larger than BufferedRNG")
+	}
+	k, err = io.ReadFull(from, b.buf[len(b.buf)-r:])
+	n += k
+	if err == nil {
+		var q [9]byte
+		k, err = io.ReadFull(from, q[:])
+		n += k
+		if err == nil && q[8] > 64 {
+			err = errors.New("crazy: saved bit count above 64")
+		}
+		if err == nil {
+			b.off = len(b.buf) - r
+			b.bits, b.nbits = binary.LittleEndian.Uint64(q[:]), uint(q[8])
+			return n, nil
+		}
+	}
+	// Leave the buffer empty rather than partially restored.
+	b.off = len(b.buf)
+	b.bits, b.nbits = 0, 0
+	return n, err
+}
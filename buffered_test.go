@@ -0,0 +1,253 @@
+package crazy
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestBufferedRNGStream(t *testing.T) {
+	a, c := NewXoshiro(), NewXoshiro()
+	a.SeedIV([]byte("buffered"))
+	c.SeedIV([]byte("buffered"))
+	b := NewBufferedRNG(a, 60)
+	if len(b.buf) != 64 {
+		t.Fatalf("buffer size is %d, want 64", len(b.buf))
+	}
+	// The underlying stream read in whole blocks.
+	want := make([]byte, 64*10)
+	for i := 0; i < len(want); i += 64 {
+		c.Read(want[i : i+64])
+	}
+	var got []byte
+	var p [8]byte
+	for i := 0; len(got) < len(want)-16; i++ {
+		switch i % 5 {
+		case 0:
+			binary.LittleEndian.PutUint64(p[:], b.Uint64())
+			got = append(got, p[:8]...)
+		case 1:
+			binary.LittleEndian.PutUint32(p[:], b.Uint32())
+			got = append(got, p[:4]...)
+		case 2:
+			binary.LittleEndian.PutUint16(p[:], b.Uint16())
+			got = append(got, p[:2]...)
+		case 3:
+			q := make([]byte, 13)
+			b.Read(q)
+			got = append(got, q...)
+		case 4:
+			x := b.Float64()
+			if want := float64(binary.LittleEndian.Uint64(want[len(got):])&(1<<53-1)) / (1 << 53); x != want {
+				t.Fatalf("Float64 is %v, want %v", x, want)
+			}
+			got = append(got, want[len(got):len(got)+8]...)
+		}
+	}
+	if !bytes.Equal(got, want[:len(got)]) {
+		t.Errorf("buffered stream differs from underlying:\n%x\n%x", got, want[:len(got)])
+	}
+}
+
+func TestBufferedRNGShortReads(t *testing.T) {
+	// One and a half blocks, delivered one byte per Read.
+	p := streamBytes(104)[:100]
+	b := NewBufferedRNG(trickle{bytes.NewReader(p)}, 64)
+	want := append(p[:100:100], make([]byte, 4)...)
+	for i := 0; i < 13; i++ {
+		if x, w := b.Uint64(), binary.LittleEndian.Uint64(want[8*i:]); x != w {
+			t.Errorf("value %d is %x, want %x", i, x, w)
+		}
+		if i < 8 && b.Err() != nil {
+			t.Fatalf("value %d: %v", i, b.Err())
+		}
+	}
+	if !errors.Is(b.Err(), io.ErrUnexpectedEOF) {
+		t.Errorf("Err is %v, want unexpected EOF", b.Err())
+	}
+	if n, err := b.Read(make([]byte, 8)); n != 0 || err != io.EOF {
+		t.Errorf("read past end returned %d, %v; want 0, EOF", n, err)
+	}
+}
+
+func TestBufferedRNGSaveRestore(t *testing.T) {
+	src := NewXoshiro()
+	src.SeedIV([]byte("save"))
+	b := NewBufferedRNG(src, 0)
+	for i := 0; i < 1000; i++ {
+		b.Uint16()
+	}
+	var s bytes.Buffer
+	n, err := b.Save(&s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 32+4+b.Buffered()+9 {
+		t.Errorf("saved %d bytes with %d buffered", n, b.Buffered())
+	}
+	r := NewBufferedRNG(NewXoshiro(), 0)
+	if _, err := r.Restore(&s); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10000; i++ {
+		if x, y := b.Uint64(), r.Uint64(); x != y {
+			t.Fatalf("value %d after restore is %x, want %x", i, y, x)
+		}
+	}
+	if _, err := r.Restore(bytes.NewReader(make([]byte, 32+4+9))); err != nil {
+		t.Errorf("restoring empty buffer: %v", err)
+	}
+	if r.Buffered() != 0 {
+		t.Errorf("%d bytes buffered after restoring empty buffer", r.Buffered())
+	}
+}
+
+func TestBufferedRNGBits(t *testing.T) {
+	a, c := NewXoshiro(), NewXoshiro()
+	a.SeedIV([]byte("bits"))
+	c.SeedIV([]byte("bits"))
+	b := NewBufferedRNG(a, 64)
+	// Bits are taken from the low end of each word in turn.
+	var w uint64
+	var left uint
+	want := func(n uint) uint64 {
+		var v uint64
+		for i := uint(0); i < n; i++ {
+			if left == 0 {
+				w, left = c.Uint64(), 64
+			}
+			v |= (w & 1) << i
+			w >>= 1
+			left--
+		}
+		return v
+	}
+	sizes := []uint{1, 3, 7, 13, 64, 0, 31, 1, 1, 63, 64, 17}
+	for i := 0; i < 100; i++ {
+		for _, n := range sizes {
+			if x, y := b.Bits(n), want(n); x != y {
+				t.Fatalf("Bits(%d) gave %x, want %x", n, x, y)
+			}
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Bits(65) did not panic")
+		}
+	}()
+	b.Bits(65)
+}
+
+func TestBufferedRNGSaveRestoreBits(t *testing.T) {
+	src := NewXoshiro()
+	src.SeedIV([]byte("save bits"))
+	b := NewBufferedRNG(src, 0)
+	b.Uint32()
+	b.Bits(5)
+	var s bytes.Buffer
+	if _, err := b.Save(&s); err != nil {
+		t.Fatal(err)
+	}
+	r := NewBufferedRNG(NewXoshiro(), 0)
+	if _, err := r.Restore(&s); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		if x, y := b.Bits(7), r.Bits(7); x != y {
+			t.Fatalf("bits %d after restore are %x, want %x", i, y, x)
+		}
+	}
+	r.SeedIV([]byte("seed"))
+	if r.nbits != 0 {
+		t.Errorf("%d bits left after seeding", r.nbits)
+	}
+}
+
+func TestBufferedRNGSeed(t *testing.T) {
+	b := NewBufferedRNG(NewXoshiro(), 0)
+	b.SeedIV([]byte("seed"))
+	x := b.Uint64()
+	b.Uint64()
+	b.SeedIV([]byte("seed"))
+	if y := b.Uint64(); x != y {
+		t.Errorf("reseeding gave %x, want %x", y, x)
+	}
+}
+
+func TestBufferedRNGNotSaver(t *testing.T) {
+	b := NewBufferedRNG(rand.Reader, 0)
+	b.Uint64()
+	if _, err := b.Save(new(bytes.Buffer)); err != ErrNotSaver {
+		t.Errorf("Save returned %v, want ErrNotSaver", err)
+	}
+	if _, err := b.Restore(new(bytes.Buffer)); err != ErrNotSaver {
+		t.Errorf("Restore returned %v, want ErrNotSaver", err)
+	}
+}
+
+var bufferedSink uint64
+
+func BenchmarkUint64Direct(b *testing.B) {
+	src := CryptoSeeded(NewXoshiro(), 32).(*Xoshiro)
+	for i := 0; i < b.N; i++ {
+		bufferedSink += src.Uint64()
+	}
+}
+
+func BenchmarkUint64RNG(b *testing.B) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	for i := 0; i < b.N; i++ {
+		bufferedSink += r.Uint64()
+	}
+}
+
+func BenchmarkUint64Buffered(b *testing.B) {
+	r := NewBufferedRNG(CryptoSeeded(NewXoshiro(), 32), 0)
+	for i := 0; i < b.N; i++ {
+		bufferedSink += r.Uint64()
+	}
+}
+
+func BenchmarkUint16RNG(b *testing.B) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	for i := 0; i < b.N; i++ {
+		bufferedSink += uint64(r.Uint16())
+	}
+}
+
+func BenchmarkUint16Buffered(b *testing.B) {
+	r := NewBufferedRNG(CryptoSeeded(NewXoshiro(), 32), 0)
+	for i := 0; i < b.N; i++ {
+		bufferedSink += uint64(r.Uint16())
+	}
+}
+
+func BenchmarkBoolRNG(b *testing.B) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	for i := 0; i < b.N; i++ {
+		if r.Bool() {
+			bufferedSink++
+		}
+	}
+}
+
+func BenchmarkBoolBuffered(b *testing.B) {
+	r := NewBufferedRNG(CryptoSeeded(NewXoshiro(), 32), 0)
+	for i := 0; i < b.N; i++ {
+		if r.Bool() {
+			bufferedSink++
+		}
+	}
+}
+
+func BenchmarkBernoulliBuffered(b *testing.B) {
+	r := NewBufferedRNG(CryptoSeeded(NewXoshiro(), 32), 0)
+	for i := 0; i < b.N; i++ {
+		if r.Bernoulli(0.3) {
+			bufferedSink++
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package crazy
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// maxEmptyReads is the number of consecutive reads returning no data and no
+// error after which CheckedRNG gives up with io.ErrNoProgress.
+const maxEmptyReads = 100
+
+// CheckedRNG wraps a Source that may fail, such as a file, pipe, network
+// stream, or hardware device. Short reads are retried until the requested
+// bytes are filled, and the first error is recorded and returned by every
+// later operation. Once an error has occurred, bytes that could not be read
+// are zeroed, and Uniform distributions and Ziggurats drawing from the
+// CheckedRNG return NaN rather than values computed from those zeros.
+//
+// CheckedRNG must be used through a pointer, and it is not safe for
+// concurrent use.
+type CheckedRNG struct {
+	src Source
+	err error
+}
+
+// NewCheckedRNG creates a checked RNG reading from src.
+func NewCheckedRNG(src Source) *CheckedRNG {
+	return &CheckedRNG{src: src}
+}
+
+// Read fills p from the underlying Source, retrying short reads. If the
+// Source fails, or makes no progress after many attempts, the error is
+// recorded, the unfilled remainder of p is zeroed, and the error is returned.
+func (c *CheckedRNG) Read(p []byte) (n int, err error) {
+	if c.err != nil {
+		zero(p)
+		return 0, c.err
+	}
+	empty := 0
+	for n < len(p) {
+		k, err := c.src.Read(p[n:])
+		n += k
+		switch {
+		case err == io.EOF && n == len(p):
+			// The Source ended exactly at the end of our request; report
+			// EOF on the next read.
+		case err != nil:
+			c.err = err
+		case k == 0:
+			empty++
+			if empty >= maxEmptyReads {
+				c.err = io.ErrNoProgress
+			}
+		default:
+			empty = 0
+		}
+		if c.err != nil {
+			zero(p[n:])
+			return n, c.err
+		}
+	}
+	return n, nil
+}
+
+// zero sets every byte of p to 0.
+func zero(p []byte) {
+	for i := range p {
+		p[i] = 0
+	}
+}
+
+// Err returns the first error encountered, if any.
+func (c *CheckedRNG) Err() error {
+	return c.err
+}
+
+// Uint64 generates a random uint64.
+func (c *CheckedRNG) Uint64() (uint64, error) {
+	var p [8]byte
+	_, err := c.Read(p[:])
+	return binary.LittleEndian.Uint64(p[:]), err
+}
+
+// Uint32 generates a random uint32.
+func (c *CheckedRNG) Uint32() (uint32, error) {
+	var p [4]byte
+	_, err := c.Read(p[:])
+	return binary.LittleEndian.Uint32(p[:]), err
+}
+
+// Uint16 generates a random uint16.
+func (c *CheckedRNG) Uint16() (uint16, error) {
+	var p [2]byte
+	_, err := c.Read(p[:])
+	return binary.LittleEndian.Uint16(p[:]), err
+}
+
+// Uint64n generates a random uint64 in the interval [0, max). It panics if
+// max == 0.
+func (c *CheckedRNG) Uint64n(max uint64) (uint64, error) {
+	v := RNG{c}.Uint64n(max)
+	if c.err != nil {
+		return 0, c.err
+	}
+	return v, nil
+}
+
+// Intn generates a random int in the interval [0, max). It panics if max <= 0.
+func (c *CheckedRNG) Intn(max int) (int, error) {
+	if max <= 0 {
+		panic("maximum zero or below")
+	}
+	v, err := c.Uint64n(uint64(max))
+	return int(v), err
+}
+
+// Float64 generates a uniform variate in [0, 1), the same as Uniform0_1.
+func (c *CheckedRNG) Float64() (float64, error) {
+	v := Uniform0_1{c}.Next()
+	return v, c.err
+}
+
+// failed reports whether src is a CheckedRNG that has encountered an error.
+func failed(src Source) bool {
+	c, ok := src.(*CheckedRNG)
+	return ok && c.err != nil
+}
+
+// CheckedDistribution pairs a Distribution with the CheckedRNG it draws from,
+// so that each variate is returned along with any error that occurred while
+// generating it.
+type CheckedDistribution struct {
+	// Dist is the distribution, which must draw from Src.
+	Dist Distribution
+	// Src is the checked source.
+	Src *CheckedRNG
+}
+
+// Next generates a variate. If the source has failed, the variate is NaN and
+// the error is returned.
+func (d CheckedDistribution) Next() (float64, error) {
+	if err := d.Src.Err(); err != nil {
+		return math.NaN(), err
+	}
+	x := d.Dist.Next()
+	if err := d.Src.Err(); err != nil {
+		return math.NaN(), err
+	}
+	return x, nil
+}
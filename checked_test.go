@@ -0,0 +1,141 @@
+package crazy
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"testing"
+)
+
+// trickle returns at most one byte per Read.
+type trickle struct {
+	r io.Reader
+}
+
+func (t trickle) Read(p []byte) (int, error) {
+	if len(p) > 1 {
+		p = p[:1]
+	}
+	return t.r.Read(p)
+}
+
+// stalled never makes progress.
+type stalled struct{}
+
+func (stalled) Read(p []byte) (int, error) {
+	return 0, nil
+}
+
+func streamBytes(n int) []byte {
+	src := NewXoshiro()
+	src.SeedIV([]byte("checked"))
+	p := make([]byte, n)
+	for i := 0; i < n; i += 8 {
+		src.Read(p[i : i+8])
+	}
+	return p
+}
+
+func TestCheckedRNGShortReads(t *testing.T) {
+	p := streamBytes(64)
+	c := NewCheckedRNG(trickle{bytes.NewReader(p)})
+	for i := 0; i < 8; i++ {
+		x, err := c.Uint64()
+		if err != nil {
+			t.Fatalf("value %d: %v", i, err)
+		}
+		want := RNG{bytes.NewReader(p[8*i:])}.Uint64()
+		if x != want {
+			t.Errorf("value %d is %x, want %x", i, x, want)
+		}
+	}
+	if _, err := c.Uint16(); err != io.EOF {
+		t.Errorf("read past end returned %v, want EOF", err)
+	}
+	if !errors.Is(c.Err(), io.EOF) {
+		t.Errorf("Err is %v, want EOF", c.Err())
+	}
+}
+
+func TestCheckedRNGSticky(t *testing.T) {
+	c := NewCheckedRNG(bytes.NewReader(streamBytes(8)[:6]))
+	x, err := c.Uint64()
+	if err != io.ErrUnexpectedEOF && err != io.EOF {
+		t.Errorf("partial read returned %v", err)
+	}
+	if x>>48 != 0 {
+		t.Errorf("unread bytes not zeroed: %x", x)
+	}
+	if v, err := c.Uint32(); err == nil || v != 0 {
+		t.Errorf("read after failure gave %x, %v", v, err)
+	}
+	if _, err := c.Intn(10); err == nil {
+		t.Error("Intn after failure returned no error")
+	}
+	if v, err := c.Float64(); err == nil || !math.IsNaN(v) {
+		t.Errorf("Float64 after failure gave %v, %v", v, err)
+	}
+}
+
+func TestCheckedRNGNoProgress(t *testing.T) {
+	c := NewCheckedRNG(stalled{})
+	if _, err := c.Uint64(); err != io.ErrNoProgress {
+		t.Errorf("stalled source returned %v, want ErrNoProgress", err)
+	}
+}
+
+func TestCheckedDistributionsNaN(t *testing.T) {
+	c := NewCheckedRNG(bytes.NewReader(nil))
+	dists := map[string]Distribution{
+		"Uniform0_1":  Uniform0_1{c},
+		"Uniform1_2":  Uniform1_2{c},
+		"Uniform":     Uniform{c, -1, 1},
+		"Normal":      NewNormal(c, 0, 1),
+		"Exponential": NewExponential(c, 1),
+		"VonMises":    NewVonMises(c, 0, 2),
+		"SkewNormal":  NewSkewNormal(c, 3, 0, 1),
+	}
+	for name, d := range dists {
+		if x := d.Next(); !math.IsNaN(x) {
+			t.Errorf("%s gave %v from failed source", name, x)
+		}
+	}
+	v := make([]float64, 3)
+	UniformSphere{c}.NextVector(v)
+	UniformSimplex{c}.NextVector(v)
+	x, err := CheckedDistribution{NewNormal(c, 0, 1), c}.Next()
+	if err != io.EOF || !math.IsNaN(x) {
+		t.Errorf("checked distribution gave %v, %v", x, err)
+	}
+	// A source that fails partway through a value leaves nonzero bytes.
+	partial := map[string]func(c *CheckedRNG) float64{
+		"Uniform0_1":           func(c *CheckedRNG) float64 { return Uniform0_1{c}.Next() },
+		"Uniform1_2":           func(c *CheckedRNG) float64 { return Uniform1_2{c}.Next() },
+		"Float32":              func(c *CheckedRNG) float64 { return float64(RNG{c}.Float32()) },
+		"Float64Open":          func(c *CheckedRNG) float64 { return RNG{c}.Float64Open() },
+		"Normal":               func(c *CheckedRNG) float64 { return NewNormal(c, 0, 1).Next() },
+		"Exponential":          func(c *CheckedRNG) float64 { return NewExponential(c, 1).Next() },
+		"Uniform0_1Full":       func(c *CheckedRNG) float64 { return Uniform0_1Full{c}.Next() },
+		"UniformClosed0_1Full": func(c *CheckedRNG) float64 { return UniformClosed0_1Full{c}.Next() },
+		"UniformClosed":        func(c *CheckedRNG) float64 { return UniformClosed{c, -1, 1}.Next() },
+		"Uniform32":            func(c *CheckedRNG) float64 { return float64(Uniform32{c, -1, 1}.Next()) },
+	}
+	for name, next := range partial {
+		c := NewCheckedRNG(bytes.NewReader([]byte{0xff, 0xff, 0xff}))
+		if x := next(c); !math.IsNaN(x) {
+			t.Errorf("%s gave %v from source failing after 3 bytes", name, x)
+		}
+	}
+}
+
+func TestCheckedDistribution(t *testing.T) {
+	c := NewCheckedRNG(CryptoSeeded(NewXoshiro(), 32))
+	d := CheckedDistribution{NewExponential(c, 2), c}
+	for i := 0; i < 100; i++ {
+		x, err := d.Next()
+		if err != nil || !(x >= 0) {
+			t.Fatalf("variate %d is %v, %v", i, x, err)
+		}
+	}
+}
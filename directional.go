@@ -0,0 +1,356 @@
+package crazy
+
+import "math"
+
+// VonMises adapts a Source to produce random angles under a von Mises
+// distribution with mean direction Mu and concentration Kappa. Variates are
+// in the interval [Mu - pi, Mu + pi], and the Analytic methods treat them as
+// real numbers in that interval.
+type VonMises struct {
+	Source
+	Mu, Kappa float64
+}
+
+// NewVonMises creates a von Mises distribution drawing from the specified
+// source with given mean direction and concentration. It panics if kappa is
+// negative.
+func NewVonMises(src Source, mu, kappa float64) VonMises {
+	if !(kappa >= 0) {
+		panic("crazy: von Mises kappa below 0")
+	}
+	return VonMises{
+		Source: src,
+		Mu:     mu,
+		Kappa:  kappa,
+	}
+}
+
+// Next generates a von Mises variate using the method of Best and Fisher,
+// "Efficient simulation of the von Mises distribution," Applied Statistics
+// 28(2), 1979.
+func (v VonMises) Next() float64 {
+	u := Uniform0_1{v.Source}
+	if v.Kappa == 0 {
+		return v.Mu + math.Pi*(2*u.Next()-1)
+	}
+	k := v.Kappa
+	s := math.Sqrt(1 + 4*k*k)
+	tau := 1 + s
+	// rho = (tau - sqrt(2 tau)) / 2k, rearranged to avoid cancellation for
+	// small k.
+	rho := 2 * k * tau / ((s + 1) * (tau + math.Sqrt(2*tau)))
+	r := (1 + rho*rho) / (2 * rho)
+	for {
+		z := math.Cos(math.Pi * u.Next())
+		f := (1 + r*z) / (r + z)
+		c := k * (r - f)
+		u2 := u.Next()
+		if c*(2-c) > u2 || math.Log(c/u2)+1-c >= 0 {
+			if u.Next() < 0.5 {
+				return v.Mu - math.Acos(f)
+			}
+			return v.Mu + math.Acos(f)
+		}
+		if failed(v.Source) {
+			return math.NaN()
+		}
+	}
+}
+
+// PDF evaluates the probability density function at x. It is zero outside
+// [Mu - pi, Mu + pi].
+func (v VonMises) PDF(x float64) float64 {
+	t := x - v.Mu
+	if t < -math.Pi || t > math.Pi {
+		return 0
+	}
+	// Use the scaled Bessel function so that large Kappa does not overflow.
+	return math.Exp(v.Kappa*(math.Cos(t)-1)) / (2 * math.Pi * besselI0e(v.Kappa))
+}
+
+// CDF evaluates the cumulative distribution function at x, using the Fourier
+// series of the density.
+func (v VonMises) CDF(x float64) float64 {
+	t := x - v.Mu
+	switch {
+	case t <= -math.Pi:
+		return 0
+	case t >= math.Pi:
+		return 1
+	}
+	p := (t + math.Pi) / (2 * math.Pi)
+	for j, r := range besselRatios(v.Kappa) {
+		p += r * math.Sin(float64(j+1)*t) / (math.Pi * float64(j+1))
+	}
+	return math.Max(0, math.Min(1, p))
+}
+
+// Quantile evaluates the inverse of the CDF at p by bisection.
+func (v VonMises) Quantile(p float64) float64 {
+	switch {
+	case p <= 0:
+		return v.Mu - math.Pi
+	case p >= 1:
+		return v.Mu + math.Pi
+	}
+	lo, hi := v.Mu-math.Pi, v.Mu+math.Pi
+	for i := 0; i < 100 && lo < hi; i++ {
+		m := 0.5 * (lo + hi)
+		if m == lo || m == hi {
+			break
+		}
+		if v.CDF(m) < p {
+			lo = m
+		} else {
+			hi = m
+		}
+	}
+	return 0.5 * (lo + hi)
+}
+
+// Mean returns Mu, the mean direction. Because variates are in
+// [Mu - pi, Mu + pi] and the density is symmetric about Mu, this is also their
+// mean as real numbers.
+func (v VonMises) Mean() float64 {
+	return v.Mu
+}
+
+// Variance returns the variance of the variates as real numbers in
+// [Mu - pi, Mu + pi]. This is not the circular variance 1 - I1(Kappa)/I0(Kappa).
+func (v VonMises) Variance() float64 {
+	s := math.Pi * math.Pi / 3
+	for j, r := range besselRatios(v.Kappa) {
+		k := float64(j + 1)
+		if j%2 == 0 {
+			r = -r
+		}
+		s += 4 * r / (k * k)
+	}
+	return s
+}
+
+// besselI0e evaluates exp(-x) I0(x), the exponentially scaled modified Bessel
+// function of the first kind of order 0, for x >= 0.
+func besselI0e(x float64) float64 {
+	if x < 30 {
+		// Power series: I0(x) = sum ((x/2)**m / m!)**2.
+		q := x * x / 4
+		s, t := 1.0, 1.0
+		for m := 1.0; t > 1e-17*s; m++ {
+			t *= q / (m * m)
+			s += t
+		}
+		return s * math.Exp(-x)
+	}
+	// Asymptotic expansion, whose terms shrink well past double precision
+	// before they start to grow for x >= 30.
+	s, t := 1.0, 1.0
+	for m := 1.0; m < 30; m++ {
+		t *= (2*m - 1) * (2*m - 1) / (8 * m * x)
+		s += t
+		if t < 1e-17*s {
+			break
+		}
+	}
+	return s / math.Sqrt(2*math.Pi*x)
+}
+
+// besselRatios returns I_j(x)/I_0(x) for j = 1, 2, ... until the ratios are
+// negligible, computed by Miller's backward recurrence.
+func besselRatios(x float64) []float64 {
+	if x <= 0 {
+		return nil
+	}
+	// I_j(x)/I_0(x) falls off like exp(-j**2/(2x)) for large x and faster
+	// for small x, so this many terms suffice, and starting the recurrence
+	// well beyond them makes it accurate.
+	n := 20 + int(10*math.Sqrt(x))
+	start := n + 30 + int(math.Sqrt(x))
+	r := make([]float64, start+2)
+	r[start] = 1e-300
+	for j := start; j > 0; j-- {
+		r[j-1] = r[j+1] + 2*float64(j)/x*r[j]
+		if r[j-1] > 1e250 {
+			for i := j - 1; i <= start; i++ {
+				r[i] *= 1e-250
+			}
+		}
+	}
+	for j := 1; j <= n; j++ {
+		r[j] /= r[0]
+	}
+	return r[1 : n+1]
+}
+
+// UniformSphere produces vectors distributed uniformly on the surface of the
+// unit sphere.
+type UniformSphere struct {
+	Source
+}
+
+// NextVector fills dst with a uniformly random unit vector. It does nothing if
+// dst is empty, since there are no unit vectors of dimension 0.
+func (s UniformSphere) NextVector(dst []float64) {
+	if len(dst) == 0 {
+		return
+	}
+	for {
+		var r float64
+		for i := range dst {
+			dst[i] = normalZig.GenNext(s.Source)
+			r += dst[i] * dst[i]
+		}
+		if r > 0 {
+			r = 1 / math.Sqrt(r)
+			for i := range dst {
+				dst[i] *= r
+			}
+			return
+		}
+		if failed(s.Source) {
+			return
+		}
+	}
+}
+
+// UniformBall produces vectors distributed uniformly within the unit ball.
+type UniformBall struct {
+	Source
+}
+
+// NextVector fills dst with a uniformly random vector of norm at most 1.
+func (b UniformBall) NextVector(dst []float64) {
+	UniformSphere{b.Source}.NextVector(dst)
+	r := math.Pow(Uniform0_1{b.Source}.Next(), 1/float64(len(dst)))
+	for i := range dst {
+		dst[i] *= r
+	}
+}
+
+// UniformSimplex produces vectors distributed uniformly on the standard
+// simplex, i.e. with nonnegative components summing to 1. This is the flat
+// Dirichlet distribution.
+type UniformSimplex struct {
+	Source
+}
+
+// NextVector fills dst with a uniformly random point on the simplex. It does
+// nothing if dst is empty.
+func (s UniformSimplex) NextVector(dst []float64) {
+	if len(dst) == 0 {
+		return
+	}
+	for {
+		var t float64
+		for i := range dst {
+			dst[i] = expoZig.GenNext(s.Source)
+			t += dst[i]
+		}
+		if t > 0 {
+			for i := range dst {
+				dst[i] /= t
+			}
+			return
+		}
+		if failed(s.Source) {
+			return
+		}
+	}
+}
+
+// VonMisesFisher produces unit vectors distributed according to a von
+// Mises-Fisher distribution with mean direction Mu and concentration Kappa.
+// The dimension of the vectors is len(Mu), which must be at least 2, and Mu
+// must have unit norm.
+type VonMisesFisher struct {
+	Source
+	Mu    []float64
+	Kappa float64
+}
+
+// NewVonMisesFisher creates a von Mises-Fisher distribution drawing from the
+// specified source. mu is copied and normalized. It panics if len(mu) < 2, if
+// mu is zero, or if kappa is negative.
+func NewVonMisesFisher(src Source, mu []float64, kappa float64) VonMisesFisher {
+	if len(mu) < 2 {
+		panic("crazy: von Mises-Fisher dimension below 2")
+	}
+	if !(kappa >= 0) {
+		panic("crazy: von Mises-Fisher kappa below 0")
+	}
+	var r float64
+	for _, v := range mu {
+		r += v * v
+	}
+	if r == 0 {
+		panic("crazy: von Mises-Fisher mean direction is zero")
+	}
+	r = 1 / math.Sqrt(r)
+	m := make([]float64, len(mu))
+	for i, v := range mu {
+		m[i] = v * r
+	}
+	return VonMisesFisher{
+		Source: src,
+		Mu:     m,
+		Kappa:  kappa,
+	}
+}
+
+// NextVector fills dst, which must have the same length as Mu, with a random
+// unit vector, using the method of Wood, "Simulation of the von Mises Fisher
+// distribution," Communications in Statistics - Simulation and Computation
+// 23(1), 1994.
+func (v VonMisesFisher) NextVector(dst []float64) {
+	if len(dst) != len(v.Mu) {
+		panic("crazy: wrong dimension for von Mises-Fisher vector")
+	}
+	if v.Kappa == 0 {
+		UniformSphere{v.Source}.NextVector(dst)
+		return
+	}
+	// Sample the component along Mu.
+	m := float64(len(dst) - 1)
+	k := v.Kappa
+	b := m / (2*k + math.Sqrt(4*k*k+m*m))
+	x0 := (1 - b) / (1 + b)
+	c := k*x0 + m*math.Log(1-x0*x0)
+	u := Uniform0_1{v.Source}
+	var w float64
+	for {
+		z := betaNext(v.Source, m/2, m/2)
+		w = (1 - (1+b)*z) / (1 - (1-b)*z)
+		if k*w+m*math.Log(1-x0*w)-c >= math.Log(u.Next()) || failed(v.Source) {
+			break
+		}
+	}
+	// Sample the orthogonal component uniformly, with the component along
+	// e1 being w.
+	UniformSphere{v.Source}.NextVector(dst[1:])
+	s := math.Sqrt(1 - w*w)
+	for i := range dst[1:] {
+		dst[i+1] *= s
+	}
+	dst[0] = w
+	// Reflect e1 onto Mu with the Householder transformation along e1 - Mu.
+	var uu, ux float64
+	for i, mu := range v.Mu {
+		d := -mu
+		if i == 0 {
+			d++
+		}
+		uu += d * d
+		ux += d * dst[i]
+	}
+	if uu < 1e-30 {
+		return
+	}
+	f := 2 * ux / uu
+	for i, mu := range v.Mu {
+		d := -mu
+		if i == 0 {
+			d++
+		}
+		dst[i] -= f * d
+	}
+}
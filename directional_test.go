@@ -0,0 +1,183 @@
+package crazy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestVonMises(t *testing.T) {
+	d := NewVonMises(CryptoSeeded(NewMT64(), mt64N), 1, 2)
+	n := 1 << 18
+	var c, s float64
+	for i := 0; i < n; i++ {
+		x := d.Next()
+		if x < 1-math.Pi || x > 1+math.Pi {
+			t.Fatalf("variate %g out of range", x)
+		}
+		c += math.Cos(x - 1)
+		s += math.Sin(x - 1)
+	}
+	// E[cos(x - mu)] = I1(kappa)/I0(kappa)
+	if c /= float64(n); math.Abs(c-0.697774657964) > 0.005 {
+		t.Errorf("mean resultant length %g", c)
+	}
+	if s /= float64(n); math.Abs(s) > 0.005 {
+		t.Errorf("mean sine %g", s)
+	}
+}
+
+func TestVonMisesSmallKappa(t *testing.T) {
+	d := NewVonMises(CryptoSeeded(NewMT64(), mt64N), 0, 1e-9)
+	for i := 0; i < 1<<16; i++ {
+		if x := d.Next(); math.IsNaN(x) || x < -math.Pi || x > math.Pi {
+			t.Fatalf("bad variate %g", x)
+		}
+	}
+}
+
+func TestUniformSphere(t *testing.T) {
+	d := UniformSphere{CryptoSeeded(NewMT64(), mt64N)}
+	x := make([]float64, 5)
+	m := make([]float64, 5)
+	n := 1 << 16
+	for i := 0; i < n; i++ {
+		d.NextVector(x)
+		if r := norm(x); math.Abs(r-1) > 1e-12 {
+			t.Fatalf("vector %v has norm %g", x, r)
+		}
+		for j, v := range x {
+			m[j] += v
+		}
+	}
+	for j, v := range m {
+		if v /= float64(n); math.Abs(v) > 0.02 {
+			t.Errorf("component %d has mean %g", j, v)
+		}
+	}
+}
+
+func TestUniformBall(t *testing.T) {
+	d := UniformBall{CryptoSeeded(NewMT64(), mt64N)}
+	x := make([]float64, 3)
+	var m float64
+	n := 1 << 16
+	for i := 0; i < n; i++ {
+		d.NextVector(x)
+		r := norm(x)
+		if r > 1 {
+			t.Fatalf("vector %v has norm %g", x, r)
+		}
+		m += r
+	}
+	// E[|x|] = d/(d+1)
+	if m /= float64(n); math.Abs(m-0.75) > 0.005 {
+		t.Errorf("mean norm %g", m)
+	}
+}
+
+func TestUniformSimplex(t *testing.T) {
+	d := UniformSimplex{CryptoSeeded(NewMT64(), mt64N)}
+	x := make([]float64, 4)
+	for i := 0; i < 1<<16; i++ {
+		d.NextVector(x)
+		var s float64
+		for _, v := range x {
+			if v < 0 {
+				t.Fatalf("negative component in %v", x)
+			}
+			s += v
+		}
+		if math.Abs(s-1) > 1e-12 {
+			t.Fatalf("vector %v sums to %g", x, s)
+		}
+	}
+}
+
+func TestVonMisesFisher(t *testing.T) {
+	mu := []float64{1, 2, -2}
+	d := NewVonMisesFisher(CryptoSeeded(NewMT64(), mt64N), mu, 5)
+	x := make([]float64, 3)
+	var m float64
+	n := 1 << 16
+	for i := 0; i < n; i++ {
+		d.NextVector(x)
+		if r := norm(x); math.Abs(r-1) > 1e-12 {
+			t.Fatalf("vector %v has norm %g", x, r)
+		}
+		m += (x[0] + 2*x[1] - 2*x[2]) / 3
+	}
+	// E[mu . x] = coth(kappa) - 1/kappa in three dimensions
+	if m /= float64(n); math.Abs(m-(1/math.Tanh(5)-0.2)) > 0.005 {
+		t.Errorf("mean projection %g", m)
+	}
+}
+
+func norm(x []float64) float64 {
+	var r float64
+	for _, v := range x {
+		r += v * v
+	}
+	return math.Sqrt(r)
+}
+
+func BenchmarkVonMises(b *testing.B) {
+	d := NewVonMises(CryptoSeeded(NewMT64(), mt64N), 0, 2)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = d.Next()
+	}
+}
+
+func TestDirectionalEmpty(t *testing.T) {
+	src := CryptoSeeded(NewMT64(), mt64N)
+	// These must return rather than reject forever.
+	UniformSphere{src}.NextVector(nil)
+	UniformBall{src}.NextVector(nil)
+	UniformSimplex{src}.NextVector(nil)
+}
+
+func TestVonMisesKappaPanics(t *testing.T) {
+	cases := map[string]func(){
+		"VonMises":       func() { NewVonMises(nil, 0, -1) },
+		"VonMisesFisher": func() { NewVonMisesFisher(nil, []float64{1, 0}, math.NaN()) },
+	}
+	for name, f := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s with negative kappa did not panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestVonMisesAnalytic(t *testing.T) {
+	src := CryptoSeeded(NewMT64(), mt64N)
+	for _, k := range []float64{0, 0.5, 2, 50, 500} {
+		d := NewVonMises(src, 1, k)
+		// Keep the test points within a few standard deviations, where the
+		// CDF is not rounded to 0 or 1.
+		sd := math.Min(1, 1/math.Sqrt(k))
+		testAnalytic(t, d, 1-2*sd, 1, 1+0.3*sd, 1+1.5*sd)
+		testMoments(t, d, 1<<16)
+		if p := d.CDF(1); math.Abs(p-0.5) > 1e-12 {
+			t.Errorf("kappa %g: CDF at the mean is %g", k, p)
+		}
+	}
+	// The density integrates to 1.
+	d := VonMises{Mu: 0, Kappa: 2}
+	var s float64
+	const m = 100000
+	for i := 0; i < m; i++ {
+		s += d.PDF(-math.Pi + (float64(i)+0.5)*2*math.Pi/m)
+	}
+	if s *= 2 * math.Pi / m; math.Abs(s-1) > 1e-9 {
+		t.Errorf("density integrates to %g", s)
+	}
+	// E[cos(x - mu)] = I1(kappa)/I0(kappa), as in TestVonMises.
+	if r := besselRatios(2)[0]; math.Abs(r-0.697774657964) > 1e-11 {
+		t.Errorf("I1(2)/I0(2) = %.12g", r)
+	}
+}
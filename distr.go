@@ -1,15 +1,37 @@
 package crazy
 
-import (
-	"encoding/binary"
-	"math"
-)
+import "math"
 
 // A Distribution adapts a source to produce floating-point numbers.
 type Distribution interface {
 	Next() float64
 }
 
+// A VectorDistribution adapts a source to produce random vectors.
+type VectorDistribution interface {
+	// NextVector fills dst with a random vector. The dimension of the vector
+	// is len(dst) unless the distribution has a fixed dimension.
+	NextVector(dst []float64)
+}
+
+// Analytic is a Distribution with known analytic properties. This allows
+// goodness-of-fit tests and transformations to use the same values that
+// produce variates.
+type Analytic interface {
+	Distribution
+	// PDF evaluates the probability density function at x.
+	PDF(x float64) float64
+	// CDF evaluates the cumulative distribution function at x.
+	CDF(x float64) float64
+	// Quantile evaluates the inverse of the CDF at p, which should be in the
+	// interval [0, 1].
+	Quantile(p float64) float64
+	// Mean returns the expected value of the distribution.
+	Mean() float64
+	// Variance returns the variance of the distribution.
+	Variance() float64
+}
+
 // Uniform1_2 produces numbers in the interval [1, 2). This interval is chosen
 // for speed. Each variate has 52 bits of precision.
 type Uniform1_2 struct {
@@ -18,9 +40,36 @@ type Uniform1_2 struct {
 
 // Next produces a uniform variate in the interval [1, 2).
 func (u Uniform1_2) Next() float64 {
-	b := [8]byte{}
-	u.Read(b[:7])
-	return math.Float64frombits(binary.LittleEndian.Uint64(b[:]) | 0x3ff0000000000000)
+	b := readUint(u.Source, 7)
+	if failed(u.Source) {
+		return math.NaN()
+	}
+	return math.Float64frombits(b | 0x3ff0000000000000)
+}
+
+// PDF evaluates the probability density function at x.
+func (u Uniform1_2) PDF(x float64) float64 {
+	return Uniform{Low: 1, High: 2}.PDF(x)
+}
+
+// CDF evaluates the cumulative distribution function at x.
+func (u Uniform1_2) CDF(x float64) float64 {
+	return Uniform{Low: 1, High: 2}.CDF(x)
+}
+
+// Quantile evaluates the inverse of the CDF at p.
+func (u Uniform1_2) Quantile(p float64) float64 {
+	return 1 + p
+}
+
+// Mean returns 1.5.
+func (u Uniform1_2) Mean() float64 {
+	return 1.5
+}
+
+// Variance returns 1/12.
+func (u Uniform1_2) Variance() float64 {
+	return 1.0 / 12
 }
 
 // Uniform0_1 produces numbers in the interval [0, 1). This interval is chosen
@@ -32,18 +81,286 @@ type Uniform0_1 struct {
 
 // Next produces a uniform variate in the interval [0, 1).
 func (u Uniform0_1) Next() float64 {
-	b := [8]byte{}
-	u.Read(b[:])
-	return float64(binary.LittleEndian.Uint64(b[:])&0x1fffffffffffff) * 1.11022302462515654042e-16
+	b := readUint(u.Source, 8)
+	if failed(u.Source) {
+		return math.NaN()
+	}
+	return float64(b&0x1fffffffffffff) * 1.11022302462515654042e-16
+}
+
+// PDF evaluates the probability density function at x.
+func (u Uniform0_1) PDF(x float64) float64 {
+	return Uniform{Low: 0, High: 1}.PDF(x)
+}
+
+// CDF evaluates the cumulative distribution function at x.
+func (u Uniform0_1) CDF(x float64) float64 {
+	return Uniform{Low: 0, High: 1}.CDF(x)
+}
+
+// Quantile evaluates the inverse of the CDF at p.
+func (u Uniform0_1) Quantile(p float64) float64 {
+	return p
+}
+
+// Mean returns 0.5.
+func (u Uniform0_1) Mean() float64 {
+	return 0.5
 }
 
-// Uniform produces numbers in the interval [Low, High).
+// Variance returns 1/12.
+func (u Uniform0_1) Variance() float64 {
+	return 1.0 / 12
+}
+
+// Uniform produces numbers in the interval [Low, High). If Low >= High, Next
+// returns Low, and if either endpoint is infinite, it returns NaN.
 type Uniform struct {
 	Source
 	Low, High float64
 }
 
+// NewUniform creates a uniform distribution drawing from the specified source
+// over [low, high). It panics if the interval is empty or its width is not
+// finite.
+func NewUniform(src Source, low, high float64) Uniform {
+	checkInterval(low, high)
+	return Uniform{Source: src, Low: low, High: high}
+}
+
+// checkInterval panics unless low < high with finite width.
+func checkInterval(low, high float64) {
+	if !(low < high) || math.IsInf(high-low, 0) {
+		panic("crazy: invalid uniform interval")
+	}
+}
+
+// lerp returns lo + (hi-lo)*t for finite lo < hi, halving the endpoints when
+// the width overflows.
+func lerp(lo, hi, t float64) float64 {
+	w := hi - lo
+	if math.IsInf(w, 0) {
+		return 2 * (lo/2 + (hi/2-lo/2)*t)
+	}
+	return lo + w*t
+}
+
 // Next produces a uniform variate.
 func (u Uniform) Next() float64 {
-	return u.High*(Uniform1_2{u.Source}.Next()-1) + u.Low
+	if !(u.Low < u.High) {
+		return u.Low
+	}
+	if math.IsInf(u.Low, 0) || math.IsInf(u.High, 0) {
+		return math.NaN()
+	}
+	d := Uniform0_1{u.Source}
+	for {
+		// Rounding can carry the result up to High, so try again in that
+		// case. Any x close enough to 0 is accepted, so this terminates.
+		if x := lerp(u.Low, u.High, d.Next()); x < u.High || x != x {
+			return x
+		}
+	}
+}
+
+// PDF evaluates the probability density function at x.
+func (u Uniform) PDF(x float64) float64 {
+	if x < u.Low || x >= u.High {
+		return 0
+	}
+	return 1 / (u.High - u.Low)
+}
+
+// CDF evaluates the cumulative distribution function at x.
+func (u Uniform) CDF(x float64) float64 {
+	switch {
+	case x <= u.Low:
+		return 0
+	case x >= u.High:
+		return 1
+	}
+	return (x - u.Low) / (u.High - u.Low)
+}
+
+// Quantile evaluates the inverse of the CDF at p.
+func (u Uniform) Quantile(p float64) float64 {
+	return u.Low + p*(u.High-u.Low)
+}
+
+// Mean returns the midpoint of the interval.
+func (u Uniform) Mean() float64 {
+	return 0.5 * (u.Low + u.High)
+}
+
+// Variance returns (High - Low)**2 / 12.
+func (u Uniform) Variance() float64 {
+	d := u.High - u.Low
+	return d * d / 12
+}
+
+// UniformOpen produces numbers in the open interval (Low, High). If no float64
+// lies strictly between Low and High, including when Low >= High, or if
+// either endpoint is infinite, Next returns NaN.
+type UniformOpen struct {
+	Source
+	Low, High float64
+}
+
+// NewUniformOpen creates a uniform distribution drawing from the specified
+// source over (low, high). It panics if no float64 lies strictly between low
+// and high or if the width of the interval is not finite.
+func NewUniformOpen(src Source, low, high float64) UniformOpen {
+	checkInterval(low, high)
+	if !(math.Nextafter(low, high) < high) {
+		panic("crazy: open uniform interval has no interior")
+	}
+	return UniformOpen{Source: src, Low: low, High: high}
+}
+
+// Next produces a uniform variate.
+func (u UniformOpen) Next() float64 {
+	if !(math.Nextafter(u.Low, u.High) < u.High) || math.IsInf(u.Low, 0) || math.IsInf(u.High, 0) {
+		return math.NaN()
+	}
+	d := Uniform0_1{u.Source}
+	for {
+		x := lerp(u.Low, u.High, d.Next())
+		if x > u.Low && x < u.High || x != x {
+			return x
+		}
+	}
+}
+
+// PDF evaluates the probability density function at x.
+func (u UniformOpen) PDF(x float64) float64 {
+	if x <= u.Low || x >= u.High {
+		return 0
+	}
+	return 1 / (u.High - u.Low)
+}
+
+// CDF evaluates the cumulative distribution function at x.
+func (u UniformOpen) CDF(x float64) float64 {
+	return Uniform{Low: u.Low, High: u.High}.CDF(x)
+}
+
+// Quantile evaluates the inverse of the CDF at p.
+func (u UniformOpen) Quantile(p float64) float64 {
+	return Uniform{Low: u.Low, High: u.High}.Quantile(p)
+}
+
+// Mean returns the midpoint of the interval.
+func (u UniformOpen) Mean() float64 {
+	return 0.5 * (u.Low + u.High)
+}
+
+// Variance returns (High - Low)**2 / 12.
+func (u UniformOpen) Variance() float64 {
+	return Uniform{Low: u.Low, High: u.High}.Variance()
+}
+
+// UniformClosed produces numbers in the closed interval [Low, High]. Each
+// endpoint occurs with probability 1/(2**53+1). If Low >= High, Next returns
+// Low, and if either endpoint is infinite, it returns NaN.
+type UniformClosed struct {
+	Source
+	Low, High float64
+}
+
+// NewUniformClosed creates a uniform distribution drawing from the specified
+// source over [low, high]. It panics if the interval is empty or its width is
+// not finite.
+func NewUniformClosed(src Source, low, high float64) UniformClosed {
+	checkInterval(low, high)
+	return UniformClosed{Source: src, Low: low, High: high}
+}
+
+// Next produces a uniform variate.
+func (u UniformClosed) Next() float64 {
+	if !(u.Low < u.High) {
+		return u.Low
+	}
+	if math.IsInf(u.Low, 0) || math.IsInf(u.High, 0) {
+		return math.NaN()
+	}
+	k := RNG{u.Source}.Uint64n(1<<53 + 1)
+	if failed(u.Source) {
+		return math.NaN()
+	}
+	x := lerp(u.Low, u.High, float64(k)*0x1p-53)
+	if x > u.High {
+		return u.High
+	}
+	return x
+}
+
+// PDF evaluates the probability density function at x.
+func (u UniformClosed) PDF(x float64) float64 {
+	if x < u.Low || x > u.High {
+		return 0
+	}
+	return 1 / (u.High - u.Low)
+}
+
+// CDF evaluates the cumulative distribution function at x.
+func (u UniformClosed) CDF(x float64) float64 {
+	return Uniform{Low: u.Low, High: u.High}.CDF(x)
+}
+
+// Quantile evaluates the inverse of the CDF at p.
+func (u UniformClosed) Quantile(p float64) float64 {
+	return Uniform{Low: u.Low, High: u.High}.Quantile(p)
+}
+
+// Mean returns the midpoint of the interval.
+func (u UniformClosed) Mean() float64 {
+	return 0.5 * (u.Low + u.High)
+}
+
+// Variance returns (High - Low)**2 / 12.
+func (u UniformClosed) Variance() float64 {
+	return Uniform{Low: u.Low, High: u.High}.Variance()
+}
+
+// Uniform32 produces float32 numbers in the interval [Low, High). Each variate
+// is drawn with 24 bits of precision from 32 bits of the source. If
+// Low >= High, Next returns Low, and if either endpoint is infinite, it
+// returns NaN.
+type Uniform32 struct {
+	Source
+	Low, High float32
+}
+
+// NewUniform32 creates a float32 uniform distribution drawing from the
+// specified source over [low, high). It panics if the interval is empty or its
+// width is not finite.
+func NewUniform32(src Source, low, high float32) Uniform32 {
+	checkInterval(float64(low), float64(high))
+	if math.IsInf(float64(high-low), 0) {
+		panic("crazy: invalid uniform interval")
+	}
+	return Uniform32{Source: src, Low: low, High: high}
+}
+
+// Next produces a uniform variate.
+func (u Uniform32) Next() float32 {
+	if !(u.Low < u.High) {
+		return u.Low
+	}
+	lo, hi := float64(u.Low), float64(u.High)
+	if math.IsInf(lo, 0) || math.IsInf(hi, 0) {
+		return float32(math.NaN())
+	}
+	r := RNG{u.Source}
+	for {
+		// Working in float64 keeps the width from overflowing. Rounding
+		// can still carry the result up to High, so try again in that case.
+		v := float64(r.Uint32()>>8) * 0x1p-24
+		if failed(u.Source) {
+			return float32(math.NaN())
+		}
+		if x := float32(lo + (hi-lo)*v); x < u.High {
+			return x
+		}
+	}
 }
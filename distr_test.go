@@ -1,6 +1,9 @@
 package crazy
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 func TestUniform1_2(t *testing.T) {
 	d := Uniform1_2{CryptoSeeded(NewMT64(), mt64N)}
@@ -28,6 +31,159 @@ func TestUniform0_1(t *testing.T) {
 	}
 }
 
+func TestUniformRange(t *testing.T) {
+	src := CryptoSeeded(NewMT64(), mt64N)
+	n := 1 << 24
+	if testing.Short() {
+		n = 1 << 20
+	}
+	cases := []struct {
+		name   string
+		next   func() float64
+		lo, hi float64
+		closed bool
+	}{
+		{"Uniform", Uniform{src, 5, 10}.Next, 5, 10, false},
+		{"UniformNegative", Uniform{src, -3, -1}.Next, -3, -1, false},
+		{"UniformOpen", UniformOpen{src, 5, 10}.Next, 5, 10, false},
+		{"UniformClosed", UniformClosed{src, 5, 10}.Next, 5, 10, true},
+	}
+	for _, c := range cases {
+		var top float64
+		for i := 0; i < n; i++ {
+			x := c.next()
+			if x < c.lo || x > c.hi || x == c.hi && !c.closed {
+				t.Fatalf("%s gave %v outside [%v, %v)", c.name, x, c.lo, c.hi)
+			}
+			top = math.Max(top, x)
+		}
+		// The old Uniform reached up to 2*High - Low; make sure the upper
+		// part of the interval is covered rather than merely not exceeded.
+		if top < c.hi-0.01 {
+			t.Errorf("%s maximum %v is far from %v", c.name, top, c.hi)
+		}
+	}
+}
+
+func TestUniform32Range(t *testing.T) {
+	d := Uniform32{CryptoSeeded(NewMT64(), mt64N), 5, 10}
+	n := 1 << 24
+	if testing.Short() {
+		n = 1 << 20
+	}
+	for i := 0; i < n; i++ {
+		if x := d.Next(); x < 5 || x >= 10 {
+			t.Fatalf("Uniform32 gave %v", x)
+		}
+	}
+}
+
+func TestUniformRounding(t *testing.T) {
+	src := CryptoSeeded(NewMT64(), mt64N)
+	// Low + (High-Low)*u rounds to High for about half of all u here.
+	hi := math.Nextafter(1, 2)
+	u := Uniform{src, 1, hi}
+	o := UniformOpen{src, 1, math.Nextafter(hi, 2)}
+	for i := 0; i < 10000; i++ {
+		if x := u.Next(); x != 1 {
+			t.Fatalf("Uniform over [1, %v) gave %v", hi, x)
+		}
+		if x := o.Next(); x != hi {
+			t.Fatalf("UniformOpen over (1, %v) gave %v", o.High, x)
+		}
+	}
+}
+
+func TestUniformClosedEndpoint(t *testing.T) {
+	w := words{^uint64(0)}
+	if x := (UniformClosed{&w, -1, 0.3}).Next(); x != 0.3 {
+		t.Errorf("UniformClosed gave %v for the largest word, want 0.3", x)
+	}
+}
+
+func TestUniformDegenerate(t *testing.T) {
+	src := CryptoSeeded(NewMT64(), mt64N)
+	// None of these may loop forever.
+	if x := (Uniform{src, 5, 5}).Next(); x != 5 {
+		t.Errorf("Uniform over [5, 5) gave %v, want 5", x)
+	}
+	if x := (Uniform{src, 10, 5}).Next(); x != 10 {
+		t.Errorf("Uniform over [10, 5) gave %v, want 10", x)
+	}
+	if x := (Uniform{src, 0, math.Inf(1)}).Next(); !math.IsNaN(x) {
+		t.Errorf("Uniform over [0, inf) gave %v, want NaN", x)
+	}
+	if x := (Uniform{src, -math.MaxFloat64, math.MaxFloat64}).Next(); math.IsInf(x, 0) || math.IsNaN(x) {
+		t.Errorf("Uniform over the full range gave %v", x)
+	}
+	if x := (UniformOpen{src, 1, math.Nextafter(1, 2)}).Next(); !math.IsNaN(x) {
+		t.Errorf("UniformOpen with no interior gave %v, want NaN", x)
+	}
+	if x := (UniformOpen{src, 5, 5}).Next(); !math.IsNaN(x) {
+		t.Errorf("UniformOpen over (5, 5) gave %v, want NaN", x)
+	}
+	if x := (UniformOpen{src, 10, 5}).Next(); !math.IsNaN(x) {
+		t.Errorf("UniformOpen over (10, 5) gave %v, want NaN", x)
+	}
+	if x := (UniformOpen{src, -math.MaxFloat64, math.MaxFloat64}).Next(); math.IsInf(x, 0) || math.IsNaN(x) {
+		t.Errorf("UniformOpen over the full range gave %v", x)
+	}
+	if x := (UniformClosed{src, 10, 5}).Next(); x != 10 {
+		t.Errorf("UniformClosed over [10, 5] gave %v, want 10", x)
+	}
+	if x := (UniformClosed{src, 0, math.Inf(1)}).Next(); !math.IsNaN(x) {
+		t.Errorf("UniformClosed over [0, inf] gave %v, want NaN", x)
+	}
+	var neg, pos int
+	for i := 0; i < 100; i++ {
+		x := (UniformClosed{src, -math.MaxFloat64, math.MaxFloat64}).Next()
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			t.Fatalf("UniformClosed over the full range gave %v", x)
+		}
+		if x < 0 {
+			neg++
+		} else {
+			pos++
+		}
+	}
+	if neg == 0 || pos == 0 {
+		t.Errorf("UniformClosed over the full range gave %d negative and %d nonnegative values", neg, pos)
+	}
+	if x := (Uniform32{src, 3, 3}).Next(); x != 3 {
+		t.Errorf("Uniform32 over [3, 3) gave %v, want 3", x)
+	}
+	if x := (Uniform32{src, 4, 3}).Next(); x != 4 {
+		t.Errorf("Uniform32 over [4, 3) gave %v, want 4", x)
+	}
+	if x := (Uniform32{src, -math.MaxFloat32, math.MaxFloat32}).Next(); math.IsInf(float64(x), 0) {
+		t.Errorf("Uniform32 over the full range gave %v", x)
+	}
+}
+
+func TestNewUniformPanics(t *testing.T) {
+	cases := map[string]func(){
+		"empty":    func() { NewUniform(nil, 1, 1) },
+		"reversed": func() { NewUniform(nil, 2, 1) },
+		"nan":      func() { NewUniformOpen(nil, math.NaN(), 1) },
+		"interior": func() { NewUniformOpen(nil, 1, math.Nextafter(1, 2)) },
+		"infinite": func() { NewUniformClosed(nil, 0, math.Inf(1)) },
+		"overflow": func() { NewUniform(nil, -math.MaxFloat64, math.MaxFloat64) },
+		"float32":  func() { NewUniform32(nil, -math.MaxFloat32, math.MaxFloat32) },
+	}
+	for name, f := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s interval did not panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+	NewUniform(nil, 5, 10)
+	NewUniform32(nil, -1, 1)
+}
+
 func BenchmarkUniform1_2(b *testing.B) {
 	d := Uniform1_2{CryptoSeeded(NewMT64(), mt64N)}
 	b.ResetTimer()
@@ -59,3 +215,64 @@ func BenchmarkUniform1_2Minus1(b *testing.B) {
 		_ = d.Next() - 1
 	}
 }
+
+// testAnalytic checks that the PDF, CDF, and Quantile methods of d are
+// consistent with each other at each of xs.
+func testAnalytic(t *testing.T, d Analytic, xs ...float64) {
+	t.Helper()
+	const h = 1e-6
+	for _, x := range xs {
+		p := d.CDF(x)
+		if q := d.Quantile(p); math.Abs(q-x) > 1e-6*math.Max(1, math.Abs(x)) {
+			t.Errorf("Quantile(CDF(%g)) = %g", x, q)
+		}
+		dp := (d.CDF(x+h) - d.CDF(x-h)) / (2 * h)
+		if f := d.PDF(x); math.Abs(f-dp) > 1e-5*math.Max(1, f) {
+			t.Errorf("PDF(%g) = %g, but CDF slope is %g", x, f, dp)
+		}
+	}
+}
+
+// momentDist is a Distribution with known mean and variance. Every Analytic
+// is one.
+type momentDist interface {
+	Distribution
+	Mean() float64
+	Variance() float64
+}
+
+// testMoments checks that the sample mean and variance of n draws from d
+// agree with d's Mean and Variance.
+func testMoments(t *testing.T, d momentDist, n int) {
+	t.Helper()
+	var m, s float64
+	for i := 1; i <= n; i++ {
+		x := d.Next()
+		dx := x - m
+		m += dx / float64(i)
+		s += dx * (x - m)
+	}
+	s /= float64(n - 1)
+	if se := math.Sqrt(d.Variance() / float64(n)); math.Abs(m-d.Mean()) > 6*se {
+		t.Errorf("sample mean %g, expected %g", m, d.Mean())
+	}
+	if v := d.Variance(); math.Abs(s-v) > 0.05*v {
+		t.Errorf("sample variance %g, expected %g", s, v)
+	}
+}
+
+func TestUniformAnalytic(t *testing.T) {
+	src := CryptoSeeded(NewMT64(), mt64N)
+	testAnalytic(t, Uniform0_1{src}, 0.1, 0.5, 0.9)
+	testAnalytic(t, Uniform1_2{src}, 1.1, 1.5, 1.9)
+	testAnalytic(t, Uniform{src, 0, 10}, 1, 5, 9)
+	testMoments(t, Uniform0_1{src}, 1<<16)
+	testMoments(t, Uniform1_2{src}, 1<<16)
+	testMoments(t, Uniform{src, 0, 10}, 1<<16)
+	testAnalytic(t, Uniform{src, 5, 10}, 6, 7.5, 9)
+	testAnalytic(t, UniformOpen{src, 5, 10}, 6, 7.5, 9)
+	testAnalytic(t, UniformClosed{src, 5, 10}, 6, 7.5, 9)
+	testMoments(t, Uniform{src, 5, 10}, 1<<16)
+	testMoments(t, UniformOpen{src, 5, 10}, 1<<16)
+	testMoments(t, UniformClosed{src, 5, 10}, 1<<16)
+}
@@ -21,8 +21,16 @@ Currently implemented PRNGs are LFG(273, 607), MT64-19937, xoroshiro128+,
 and xoshiro256**. io.Reader and, in particular, crypto/rand.Reader naturally
 implement Source.
 
-The only currently implemented distributions are normal and exponential, but
-the ziggurat directory contains a Python script to calculate the necessary
-parameters for any monotonically decreasing distribution.
+Implemented distributions include uniform, normal, exponential, skew-normal,
+alpha-stable, generalized extreme value, and von Mises, along with vector
+distributions on spheres, balls, and simplices. Distributions implementing
+Analytic also provide their PDF, CDF, quantile function, and moments.
+Uniform0_1Full and its open and closed variants can produce every float64 in
+the unit interval, and Normal and Exponential use them in their tails with
+NextFull. Other distributions can be built from a quantile function, a CDF, or
+a density using InverseTransform, NumericInversion, Rejection, or ARS, and the
+ziggurat directory contains a Python script to calculate the necessary
+parameters for a Ziggurat for any monotonically decreasing distribution. Alias
+selects indices with given weights in constant time.
 */
 package crazy
@@ -24,6 +24,45 @@ func (e Exponential) Next() float64 {
 	return x / e.Rate
 }
 
+// NextFull generates an exponential variate whose tail beyond the ziggurat is
+// drawn using a full-precision uniform. It is as fast as Next except in the
+// rare tail case, where Next can produce no variates beyond about 46 / Rate.
+func (e Exponential) NextFull() float64 {
+	x := expoZigFull.GenNext(e.Source)
+	return x / e.Rate
+}
+
+// PDF evaluates the probability density function at x.
+func (e Exponential) PDF(x float64) float64 {
+	if x < 0 {
+		return 0
+	}
+	return e.Rate * math.Exp(-e.Rate*x)
+}
+
+// CDF evaluates the cumulative distribution function at x.
+func (e Exponential) CDF(x float64) float64 {
+	if x < 0 {
+		return 0
+	}
+	return -math.Expm1(-e.Rate * x)
+}
+
+// Quantile evaluates the inverse of the CDF at p.
+func (e Exponential) Quantile(p float64) float64 {
+	return -math.Log1p(-p) / e.Rate
+}
+
+// Mean returns 1/Rate.
+func (e Exponential) Mean() float64 {
+	return 1 / e.Rate
+}
+
+// Variance returns 1/Rate**2.
+func (e Exponential) Variance() float64 {
+	return 1 / (e.Rate * e.Rate)
+}
+
 func expoPDF(x float64) float64 {
 	return math.Exp(-x)
 }
@@ -22,3 +22,9 @@ func BenchmarkExponential(b *testing.B) {
 		_ = d.Next()
 	}
 }
+
+func TestExponentialAnalytic(t *testing.T) {
+	d := NewExponential(CryptoSeeded(NewMT64(), mt64N), 2)
+	testAnalytic(t, d, 0.01, 0.5, 1, 4, 10)
+	testMoments(t, d, 1<<16)
+}
@@ -0,0 +1,208 @@
+package crazy
+
+import (
+	"math"
+	"math/bits"
+)
+
+// fullUniform generates a uniform variate in the closed interval [0, 1] such
+// that every representable float64 in the interval can be produced, each with
+// probability equal to the width of the interval of reals that round to it.
+// The exponent is chosen by counting leading zero bits of a random stream,
+// then the mantissa is filled with random bits. When the mantissa is zero, the
+// value is rounded up to the next binade with probability 1/2, which gives the
+// powers of two their correct share and makes 1 possible. See Downey,
+// "Generating pseudo-random floating-point values," 2007.
+func fullUniform(src Source) float64 {
+	r := RNG{src}
+	x := r.Uint64()
+	m := x & (1<<52 - 1)
+	// The 11 bits above the mantissa begin the geometric exponent, and the
+	// top bit decides rounding.
+	e := uint64(1022)
+	if g := x >> 52 & 0x7ff; g != 0 {
+		e -= uint64(bits.TrailingZeros64(g))
+	} else {
+		e -= 11
+		for e > 0 {
+			w := r.Uint64()
+			if w != 0 {
+				z := uint64(bits.TrailingZeros64(w))
+				if z > e {
+					z = e
+				}
+				e -= z
+				break
+			}
+			if e < 64 {
+				e = 0
+				break
+			}
+			e -= 64
+		}
+	}
+	if failed(src) {
+		return math.NaN()
+	}
+	if m == 0 {
+		e += x >> 63
+	}
+	return math.Float64frombits(e<<52 | m)
+}
+
+// Uniform0_1Full produces numbers in the interval [0, 1) with full precision:
+// every representable float64 in the interval can occur, including values
+// below 2**-53, with probability equal to the width of the interval of reals
+// that round to it. It is slower than Uniform0_1, which produces only
+// multiples of 2**-53.
+type Uniform0_1Full struct {
+	Source
+}
+
+// Next produces a uniform variate in the interval [0, 1).
+func (u Uniform0_1Full) Next() float64 {
+	for {
+		if x := fullUniform(u.Source); x != 1 {
+			return x
+		}
+	}
+}
+
+// PDF evaluates the probability density function at x.
+func (u Uniform0_1Full) PDF(x float64) float64 {
+	return Uniform0_1{}.PDF(x)
+}
+
+// CDF evaluates the cumulative distribution function at x.
+func (u Uniform0_1Full) CDF(x float64) float64 {
+	return Uniform0_1{}.CDF(x)
+}
+
+// Quantile evaluates the inverse of the CDF at p.
+func (u Uniform0_1Full) Quantile(p float64) float64 {
+	return p
+}
+
+// Mean returns 0.5.
+func (u Uniform0_1Full) Mean() float64 {
+	return 0.5
+}
+
+// Variance returns 1/12.
+func (u Uniform0_1Full) Variance() float64 {
+	return 1.0 / 12
+}
+
+// UniformOpen0_1Full is like Uniform0_1Full, but produces numbers in the open
+// interval (0, 1). It is suitable for taking logarithms.
+type UniformOpen0_1Full struct {
+	Source
+}
+
+// Next produces a uniform variate in the interval (0, 1).
+func (u UniformOpen0_1Full) Next() float64 {
+	for {
+		if x := fullUniform(u.Source); x != 0 && x != 1 {
+			return x
+		}
+	}
+}
+
+// PDF evaluates the probability density function at x.
+func (u UniformOpen0_1Full) PDF(x float64) float64 {
+	return Uniform0_1{}.PDF(x)
+}
+
+// CDF evaluates the cumulative distribution function at x.
+func (u UniformOpen0_1Full) CDF(x float64) float64 {
+	return Uniform0_1{}.CDF(x)
+}
+
+// Quantile evaluates the inverse of the CDF at p.
+func (u UniformOpen0_1Full) Quantile(p float64) float64 {
+	return p
+}
+
+// Mean returns 0.5.
+func (u UniformOpen0_1Full) Mean() float64 {
+	return 0.5
+}
+
+// Variance returns 1/12.
+func (u UniformOpen0_1Full) Variance() float64 {
+	return 1.0 / 12
+}
+
+// UniformClosed0_1Full is like Uniform0_1Full, but produces numbers in the
+// closed interval [0, 1]. 1 occurs with probability 2**-54.
+type UniformClosed0_1Full struct {
+	Source
+}
+
+// Next produces a uniform variate in the interval [0, 1].
+func (u UniformClosed0_1Full) Next() float64 {
+	return fullUniform(u.Source)
+}
+
+// PDF evaluates the probability density function at x.
+func (u UniformClosed0_1Full) PDF(x float64) float64 {
+	if x == 1 {
+		return 1
+	}
+	return Uniform0_1{}.PDF(x)
+}
+
+// CDF evaluates the cumulative distribution function at x.
+func (u UniformClosed0_1Full) CDF(x float64) float64 {
+	return Uniform0_1{}.CDF(x)
+}
+
+// Quantile evaluates the inverse of the CDF at p.
+func (u UniformClosed0_1Full) Quantile(p float64) float64 {
+	return p
+}
+
+// Mean returns 0.5.
+func (u UniformClosed0_1Full) Mean() float64 {
+	return 0.5
+}
+
+// Variance returns 1/12.
+func (u UniformClosed0_1Full) Variance() float64 {
+	return 1.0 / 12
+}
+
+// normalTailFull is normalTail using full-precision uniforms, so that the
+// tail extends as far as float64 allows rather than being cut off where
+// Uniform0_1 runs out of bits.
+func normalTailFull(src Source) float64 {
+	dist := UniformOpen0_1Full{src}
+	for {
+		x := -math.Log(dist.Next()) / normalR
+		y := -math.Log(dist.Next())
+		if y+y >= x*x {
+			return x + normalR
+		}
+		if failed(src) {
+			return math.NaN()
+		}
+	}
+}
+
+// expoTailFull is expoTail using full-precision uniforms.
+func expoTailFull(src Source) float64 {
+	return expoR - math.Log(UniformOpen0_1Full{src}.Next())
+}
+
+// normalZigFull and expoZigFull are the normal and exponential ziggurats with
+// full-precision tails.
+var (
+	normalZigFull = withTail(&normalZig, normalTailFull)
+	expoZigFull   = withTail(&expoZig, expoTailFull)
+)
+
+func withTail(z *Ziggurat, tail func(Source) float64) *Ziggurat {
+	r := *z
+	r.Tail = tail
+	return &r
+}
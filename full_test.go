@@ -0,0 +1,123 @@
+package crazy
+
+import (
+	"math"
+	"testing"
+)
+
+// words is a Source producing a fixed sequence of 64-bit values, then zeros.
+type words []uint64
+
+func (w *words) Uint64() uint64 {
+	if len(*w) == 0 {
+		return 0
+	}
+	x := (*w)[0]
+	*w = (*w)[1:]
+	return x
+}
+
+func (w *words) Read(p []byte) (int, error) {
+	var b [8]byte
+	for i := range p {
+		if i%8 == 0 {
+			x := w.Uint64()
+			for j := range b {
+				b[j] = byte(x >> (8 * j))
+			}
+		}
+		p[i] = b[i%8]
+	}
+	return len(p), nil
+}
+
+func TestFullUniformExact(t *testing.T) {
+	cases := []struct {
+		name string
+		w    words
+		want float64
+	}{
+		{"half", words{1 << 52}, 0.5},
+		{"mantissa", words{1<<52 | 3}, 0.5 + 3*0x1p-53},
+		{"quarter", words{1 << 53}, 0.25},
+		{"round up", words{1<<63 | 1<<52}, 1},
+		{"round up quarter", words{1<<63 | 1<<53}, 0.5},
+		{"second word", words{5, 1 << 4}, (1 + 5*0x1p-52) * 0x1p-16},
+		{"deep", words{7, 0, 0, 1}, (1 + 7*0x1p-52) * math.Pow(2, -1-11-128)},
+		{"subnormal", words{9}, 9 * 0x1p-1074},
+		{"zero", words{0}, 0},
+	}
+	for _, c := range cases {
+		w := c.w
+		if got := fullUniform(&w); got != c.want {
+			t.Errorf("%s: got %v (%x), want %v", c.name, got, math.Float64bits(got), c.want)
+		}
+	}
+}
+
+func TestFullUniformIntervals(t *testing.T) {
+	w := words{1<<63 | 1<<52, 1 << 52}
+	if x := (Uniform0_1Full{&w}).Next(); x != 0.5 {
+		t.Errorf("Uniform0_1Full gave %v, want 1 to be redrawn", x)
+	}
+	w = words{1<<63 | 1<<52}
+	if x := (UniformClosed0_1Full{&w}).Next(); x != 1 {
+		t.Errorf("UniformClosed0_1Full gave %v, want 1", x)
+	}
+	// Zero takes 17 words to produce.
+	w = make(words, 18)
+	w[17] = 1<<52 | 1
+	if x := (UniformOpen0_1Full{&w}).Next(); x != 0.5+0x1p-53 {
+		t.Errorf("UniformOpen0_1Full gave %v, want 0 to be redrawn", x)
+	}
+}
+
+func TestFullUniformDistribution(t *testing.T) {
+	u := Uniform0_1Full{CryptoSeeded(NewXoshiro(), 32)}
+	testMoments(t, u, 100000)
+	// Values far below the resolution of a 53-bit grid occur with the right
+	// frequency: P(u < 2**-k) = 2**-k, and such values are not multiples of
+	// 2**-53 times a small integer.
+	const n = 1 << 20
+	var small, fine int
+	for i := 0; i < n; i++ {
+		x := u.Next()
+		if x < 0x1p-10 {
+			small++
+			if x*0x1p53 != math.Floor(x*0x1p53) {
+				fine++
+			}
+		}
+	}
+	if d := float64(small) - n/1024.0; math.Abs(d) > 5*math.Sqrt(n/1024.0) {
+		t.Errorf("%d values below 2**-10, want about %d", small, n/1024)
+	}
+	if fine < small/2 {
+		t.Errorf("only %d of %d small values have bits below 2**-53", fine, small)
+	}
+}
+
+func TestNextFull(t *testing.T) {
+	src := CryptoSeeded(NewXoshiro(), 32)
+	testMoments(t, fullDist{NewNormal(src, 2, 3)}, 100000)
+	testMoments(t, fullExpo{NewExponential(src, 4)}, 100000)
+}
+
+type fullDist struct{ Normal }
+
+func (d fullDist) Next() float64 { return d.NextFull() }
+
+type fullExpo struct{ Exponential }
+
+func (d fullExpo) Next() float64 { return d.NextFull() }
+
+func TestExpoTailFull(t *testing.T) {
+	// A uniform just above 2**-100 puts the exponential tail well beyond where
+	// Uniform0_1 can reach.
+	w := words{1, 0, 1 << 24}
+	x := expoTailFull(&w)
+	want := expoR + 100*math.Ln2 - math.Log1p(0x1p-52)
+	if math.Abs(x-want) > 1e-12 {
+		t.Errorf("tail gave %v, want %v", x, want)
+	}
+}
@@ -0,0 +1,39 @@
+package crazy
+
+import "math"
+
+// gammaNext generates a gamma variate with the given shape and unit scale
+// using the method of Marsaglia and Tsang, "A simple method for generating
+// gamma variables," ACM TOMS 26(3), 2000.
+func gammaNext(src Source, shape float64) float64 {
+	if shape < 1 {
+		// Boost the shape and correct with a power of a uniform variate.
+		u := Uniform0_1{src}.Next()
+		return gammaNext(src, shape+1) * math.Pow(u, 1/shape)
+	}
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	u := Uniform0_1{src}
+	for {
+		x := normalZig.GenNext(src)
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		if failed(src) {
+			return math.NaN()
+		}
+		v = v * v * v
+		w := u.Next()
+		if w < 1-0.0331*x*x*x*x || math.Log(w) < 0.5*x*x+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
+
+// betaNext generates a beta variate with the given shape parameters.
+func betaNext(src Source, a, b float64) float64 {
+	x := gammaNext(src, a)
+	y := gammaNext(src, b)
+	return x / (x + y)
+}
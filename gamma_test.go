@@ -0,0 +1,24 @@
+package crazy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGammaNext(t *testing.T) {
+	src := CryptoSeeded(NewMT64(), mt64N)
+	for _, shape := range []float64{0.3, 1, 4.5} {
+		n := 1 << 17
+		var m, s float64
+		for i := 0; i < n; i++ {
+			x := gammaNext(src, shape)
+			m += x
+			s += x * x
+		}
+		m /= float64(n)
+		s = s/float64(n) - m*m
+		if math.Abs(m-shape) > 0.02*shape || math.Abs(s-shape) > 0.05*shape {
+			t.Errorf("shape %g: mean %g, variance %g", shape, m, s)
+		}
+	}
+}
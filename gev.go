@@ -0,0 +1,150 @@
+package crazy
+
+import "math"
+
+// GEV adapts a Source to produce random numbers under a generalized extreme
+// value distribution with shape Xi, location Mu, and scale Sigma. Xi = 0 gives
+// the Gumbel distribution, Xi > 0 the Fréchet, and Xi < 0 the reversed
+// Weibull.
+type GEV struct {
+	Source
+	Xi, Mu, Sigma float64
+}
+
+// NewGEV creates a generalized extreme value distribution drawing from the
+// specified source with given shape, location, and scale. It panics if sigma
+// is not positive.
+func NewGEV(src Source, xi, mu, sigma float64) GEV {
+	if !(sigma > 0) {
+		panic("crazy: GEV scale not positive")
+	}
+	return GEV{
+		Source: src,
+		Xi:     xi,
+		Mu:     mu,
+		Sigma:  sigma,
+	}
+}
+
+// Next generates a GEV variate.
+func (g GEV) Next() float64 {
+	e := expoZig.GenNext(g.Source)
+	return g.Mu + g.Sigma*g.fromExpo(e)
+}
+
+// fromExpo maps a standard exponential variate (i.e. -log(u)) to a standard
+// GEV variate. The Xi = 0 limit is approached continuously.
+func (g GEV) fromExpo(e float64) float64 {
+	if g.Xi == 0 {
+		return -math.Log(e)
+	}
+	return math.Expm1(-g.Xi*math.Log(e)) / g.Xi
+}
+
+// t evaluates the standardized tail function t(x) such that CDF = exp(-t).
+// ok is false if x is outside the support.
+func (g GEV) t(x float64) (t float64, ok bool) {
+	s := (x - g.Mu) / g.Sigma
+	if g.Xi == 0 {
+		return math.Exp(-s), true
+	}
+	v := g.Xi * s
+	if v <= -1 {
+		return 0, false
+	}
+	return math.Exp(-math.Log1p(v) / g.Xi), true
+}
+
+// PDF evaluates the probability density function at x.
+func (g GEV) PDF(x float64) float64 {
+	t, ok := g.t(x)
+	if !ok || t == 0 || math.IsInf(t, 1) {
+		return 0
+	}
+	return math.Exp((g.Xi+1)*math.Log(t)-t) / g.Sigma
+}
+
+// CDF evaluates the cumulative distribution function at x.
+func (g GEV) CDF(x float64) float64 {
+	t, ok := g.t(x)
+	if !ok {
+		// Below the lower bound for Xi > 0, above the upper for Xi < 0.
+		if g.Xi > 0 {
+			return 0
+		}
+		return 1
+	}
+	return math.Exp(-t)
+}
+
+// Quantile evaluates the inverse of the CDF at p.
+func (g GEV) Quantile(p float64) float64 {
+	return g.Mu + g.Sigma*g.fromExpo(-math.Log(p))
+}
+
+// Mean returns the expected value of the distribution, which is +Inf if
+// Xi >= 1.
+func (g GEV) Mean() float64 {
+	switch {
+	case g.Xi == 0:
+		return g.Mu + g.Sigma*eulerGamma
+	case g.Xi >= 1:
+		return math.Inf(1)
+	case math.Abs(g.Xi) < gevSeriesXi:
+		// Gamma(1-xi) - 1 cancels catastrophically near zero.
+		return g.Mu + g.Sigma*math.Expm1(lgamma1m(g.Xi))/g.Xi
+	}
+	return g.Mu + g.Sigma*(math.Gamma(1-g.Xi)-1)/g.Xi
+}
+
+// Variance returns the variance of the distribution, which is +Inf if
+// Xi >= 1/2.
+func (g GEV) Variance() float64 {
+	switch {
+	case g.Xi == 0:
+		return g.Sigma * g.Sigma * math.Pi * math.Pi / 6
+	case g.Xi >= 0.5:
+		return math.Inf(1)
+	}
+	g1 := math.Gamma(1 - g.Xi)
+	if math.Abs(g.Xi) < gevSeriesXi {
+		// Gamma(1-2xi) - Gamma(1-xi)**2 cancels catastrophically near zero.
+		// Use lgamma(1-2xi) - 2 lgamma(1-xi) = sum (2**k-2)/k zeta(k) xi**k.
+		x := g.Xi
+		var d float64
+		for k := len(zeta) - 1; k >= 2; k-- {
+			d = d*x + (math.Ldexp(1, k)-2)/float64(k)*zeta[k]
+		}
+		return g.Sigma * g.Sigma * g1 * g1 * math.Expm1(d*x*x) / (x * x)
+	}
+	g2 := math.Gamma(1 - 2*g.Xi)
+	return g.Sigma * g.Sigma * (g2 - g1*g1) / (g.Xi * g.Xi)
+}
+
+// gevSeriesXi is the magnitude of Xi below which GEV moments are computed by
+// series.
+const gevSeriesXi = 0.01
+
+// lgamma1m evaluates log(Gamma(1-x)) for small x by its Taylor series,
+// gamma*x + sum zeta(k)/k x**k, which avoids rounding 1-x.
+func lgamma1m(x float64) float64 {
+	var s float64
+	for k := len(zeta) - 1; k >= 2; k-- {
+		s = s*x + zeta[k]/float64(k)
+	}
+	return x * (eulerGamma + s*x)
+}
+
+// zeta[k] is the Riemann zeta function at k for k >= 2.
+var zeta = [...]float64{
+	2: 1.6449340668482264,
+	3: 1.2020569031595943,
+	4: 1.0823232337111382,
+	5: 1.0369277551433699,
+	6: 1.0173430619844491,
+	7: 1.0083492773819228,
+	8: 1.0040773561979443,
+}
+
+// eulerGamma is the Euler-Mascheroni constant.
+const eulerGamma = 0.57721566490153286060651209008240243
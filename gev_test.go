@@ -0,0 +1,48 @@
+package crazy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGEVAnalytic(t *testing.T) {
+	src := CryptoSeeded(NewMT64(), mt64N)
+	for _, xi := range []float64{-0.3, -1e-9, 0, 1e-9, 0.05} {
+		d := NewGEV(src, xi, 1, 2)
+		testAnalytic(t, d, -1, 0, 1, 3, 5)
+		testMoments(t, d, 1<<18)
+	}
+}
+
+func TestGEVLimit(t *testing.T) {
+	// Values near Xi = 0 should approach the Gumbel distribution.
+	g, d := GEV{Xi: 0, Mu: 0, Sigma: 1}, GEV{Xi: 1e-12, Mu: 0, Sigma: 1}
+	for x := -2.0; x <= 5; x += 0.5 {
+		if a, b := g.CDF(x), d.CDF(x); math.Abs(a-b) > 1e-10 {
+			t.Errorf("CDF(%g): Gumbel %g, Xi=1e-12 %g", x, a, b)
+		}
+	}
+	for _, p := range []float64{0.01, 0.5, 0.99} {
+		if a, b := g.Quantile(p), d.Quantile(p); math.Abs(a-b) > 1e-10 {
+			t.Errorf("Quantile(%g): Gumbel %g, Xi=1e-12 %g", p, a, b)
+		}
+	}
+}
+
+func TestGEVSupport(t *testing.T) {
+	src := CryptoSeeded(NewMT64(), mt64N)
+	// Xi > 0 is bounded below by Mu - Sigma/Xi; Xi < 0 above.
+	lo := NewGEV(src, 0.5, 0, 1)
+	hi := NewGEV(src, -0.5, 0, 1)
+	for i := 0; i < 1<<16; i++ {
+		if x := lo.Next(); x < -2 {
+			t.Fatalf("variate %g below lower bound", x)
+		}
+		if x := hi.Next(); x > 2 {
+			t.Fatalf("variate %g above upper bound", x)
+		}
+	}
+	if lo.CDF(-3) != 0 || hi.CDF(3) != 1 || lo.PDF(-3) != 0 || hi.PDF(3) != 0 {
+		t.Error("wrong CDF or PDF outside support")
+	}
+}
@@ -0,0 +1,180 @@
+package crazy
+
+import (
+	"math"
+	"sort"
+)
+
+// InverseTransform produces variates of any distribution with a known
+// quantile function by applying it to uniform variates in [0, 1).
+type InverseTransform struct {
+	Source
+	// Quantile is the inverse of the cumulative distribution function of the
+	// desired distribution.
+	Quantile func(p float64) float64
+}
+
+// NewInverseTransform creates an inverse transform sampler drawing from the
+// specified source with the given quantile function.
+func NewInverseTransform(src Source, quantile func(p float64) float64) InverseTransform {
+	return InverseTransform{
+		Source:   src,
+		Quantile: quantile,
+	}
+}
+
+// Next generates a variate.
+func (d InverseTransform) Next() float64 {
+	return d.Quantile(Uniform0_1{d.Source}.Next())
+}
+
+// NumericInversion produces variates of a distribution with a known CDF and
+// PDF by interpolating the inverse of the CDF. The interpolation is piecewise
+// cubic Hermite, with intervals chosen adaptively such that the error in u at
+// test points within each interval is within a given tolerance. See Hörmann and
+// Leydold, "Continuous random variate generation by fast numerical
+// inversion," ACM TOMACS 13(4), 2003.
+type NumericInversion struct {
+	Source
+	// u[i] = CDF(x[i]); f[i] = PDF(x[i]), both normalized to the interval
+	// [lo, hi] so that u runs from 0 to 1 over the truncated distribution.
+	u, x, f []float64
+}
+
+// NewNumericInversion creates a numeric inversion sampler drawing from the
+// specified source. The distribution is truncated to [lo, hi], which must be
+// finite; tol is the maximum error in u, with values around 1e-10 being
+// reasonable. It panics if lo >= hi, if tol is not positive, or if the CDF is
+// not increasing over [lo, hi].
+func NewNumericInversion(src Source, cdf, pdf func(x float64) float64, lo, hi, tol float64) *NumericInversion {
+	if !(lo < hi) || math.IsInf(lo, 0) || math.IsInf(hi, 0) {
+		panic("crazy: invalid interval for numeric inversion")
+	}
+	if !(tol > 0) {
+		panic("crazy: numeric inversion tolerance not positive")
+	}
+	ulo, uhi := cdf(lo), cdf(hi)
+	if !(ulo < uhi) {
+		panic("crazy: CDF not increasing over interval")
+	}
+	d := &NumericInversion{Source: src}
+	// Work in normalized u so that truncation is accounted for.
+	scale := uhi - ulo
+	nc := func(x float64) float64 { return (cdf(x) - ulo) / scale }
+	npdf := func(x float64) float64 { return pdf(x) / scale }
+	d.add(0, lo, npdf(lo))
+	d.refine(nc, npdf, lo, 0, npdf(lo), hi, 1, npdf(hi), tol, (hi-lo)*1e-12)
+	d.add(1, hi, npdf(hi))
+	return d
+}
+
+// add appends a knot.
+func (d *NumericInversion) add(u, x, f float64) {
+	d.u = append(d.u, u)
+	d.x = append(d.x, x)
+	d.f = append(d.f, f)
+}
+
+// refine recursively splits [a, b] until the Hermite interpolant is accurate,
+// appending each interior knot in order.
+func (d *NumericInversion) refine(cdf, pdf func(float64) float64, a, ua, fa, b, ub, fb, tol, minw float64) {
+	if b-a > minw {
+		um := 0.5 * (ua + ub)
+		xm := hermite(0.5, a, b, ua, ub, fa, fb)
+		ok := xm > a && xm < b && math.Abs(cdf(xm)-um) <= tol
+		if ok {
+			// Also check the quarter points, which catches most interpolants
+			// that are accurate at the midpoint by coincidence.
+			x1 := hermite(0.25, a, b, ua, ub, fa, fb)
+			x3 := hermite(0.75, a, b, ua, ub, fa, fb)
+			ok = a < x1 && x1 < xm && xm < x3 && x3 < b &&
+				math.Abs(cdf(x1)-(ua+0.25*(ub-ua))) <= tol &&
+				math.Abs(cdf(x3)-(ua+0.75*(ub-ua))) <= tol
+		}
+		if !ok {
+			m := 0.5 * (a + b)
+			um, fm := cdf(m), pdf(m)
+			d.refine(cdf, pdf, a, ua, fa, m, um, fm, tol, minw)
+			d.add(um, m, fm)
+			d.refine(cdf, pdf, m, um, fm, b, ub, fb, tol, minw)
+		}
+	}
+}
+
+// hermite evaluates the cubic Hermite interpolant of the inverse CDF over
+// [a, b] at the fraction t of [ua, ub]. If the derivative at either end is
+// unusable, it falls back to linear interpolation.
+func hermite(t, a, b, ua, ub, fa, fb float64) float64 {
+	du := ub - ua
+	if fa <= 0 || fb <= 0 || du <= 0 {
+		return a + t*(b-a)
+	}
+	da, db := du/fa, du/fb
+	t2 := t * t
+	t3 := t2 * t
+	return (2*t3-3*t2+1)*a + (t3-2*t2+t)*da + (-2*t3+3*t2)*b + (t3-t2)*db
+}
+
+// Quantile evaluates the interpolated inverse CDF at p.
+func (d *NumericInversion) Quantile(p float64) float64 {
+	i := sort.SearchFloat64s(d.u, p)
+	switch {
+	case i <= 0:
+		return d.x[0]
+	case i >= len(d.u):
+		return d.x[len(d.x)-1]
+	}
+	ua, ub := d.u[i-1], d.u[i]
+	t := (p - ua) / (ub - ua)
+	return hermite(t, d.x[i-1], d.x[i], ua, ub, d.f[i-1], d.f[i])
+}
+
+// Next generates a variate.
+func (d *NumericInversion) Next() float64 {
+	return d.Quantile(Uniform0_1{d.Source}.Next())
+}
+
+// solveQuantile finds x such that cdf(x) = p using Newton's method safeguarded
+// by bisection. x0 is an initial guess and scale is a typical distance over
+// which the CDF changes significantly. The distribution must be supported on
+// the whole real line.
+func solveQuantile(cdf, pdf func(float64) float64, p, x0, scale float64) float64 {
+	switch {
+	case p <= 0:
+		return math.Inf(-1)
+	case p >= 1:
+		return math.Inf(1)
+	}
+	// Bracket the root.
+	lo, hi := x0, x0
+	if cdf(x0) < p {
+		for step := scale; cdf(hi) < p; step *= 2 {
+			lo, hi = hi, hi+step
+		}
+	} else {
+		for step := scale; cdf(lo) >= p; step *= 2 {
+			lo, hi = lo-step, lo
+		}
+	}
+	x := 0.5 * (lo + hi)
+	for i := 0; i < 200 && lo < hi; i++ {
+		f := cdf(x) - p
+		switch {
+		case f < 0:
+			lo = x
+		case f > 0:
+			hi = x
+		default:
+			return x
+		}
+		y := x - f/pdf(x)
+		if !(y > lo && y < hi) {
+			y = 0.5 * (lo + hi)
+		}
+		if y == x || math.Abs(y-x) <= 1e-15*math.Abs(x) {
+			return y
+		}
+		x = y
+	}
+	return x
+}
@@ -0,0 +1,120 @@
+package crazy
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestInverseTransform(t *testing.T) {
+	e := Exponential{Rate: 1}
+	d := NewInverseTransform(CryptoSeeded(NewMT64(), mt64N), e.Quantile)
+	n := 1 << 16
+	x := make([]float64, n)
+	for i := range x {
+		if x[i] = d.Next(); x[i] < 0 || math.IsInf(x[i], 0) {
+			t.Fatalf("bad variate %g", x[i])
+		}
+	}
+	// Kolmogorov-Smirnov test against the target CDF. The 99.9th percentile
+	// of sqrt(n) D is about 1.95.
+	sort.Float64s(x)
+	var D float64
+	for i, v := range x {
+		c := e.CDF(v)
+		D = math.Max(D, math.Max(c-float64(i)/float64(n), float64(i+1)/float64(n)-c))
+	}
+	if D*math.Sqrt(float64(n)) > 1.95 {
+		t.Errorf("KS statistic %g too large for %d samples", D, n)
+	}
+}
+
+func TestNumericInversion(t *testing.T) {
+	nd := Normal{Mu: 0, Sigma: 1}
+	d := NewNumericInversion(CryptoSeeded(NewMT64(), mt64N), nd.CDF, nd.PDF, -8, 8, 1e-12)
+	for p := 0.001; p < 1; p += 0.001 {
+		if x, q := d.Quantile(p), nd.Quantile(p); math.Abs(x-q) > 1e-8 {
+			t.Errorf("Quantile(%g) = %g, want %g", p, x, q)
+		}
+	}
+	for i := 0; i < 1<<16; i++ {
+		if x := d.Next(); x < -8 || x > 8 {
+			t.Fatalf("variate %g out of range", x)
+		}
+	}
+}
+
+func TestNumericInversionTruncated(t *testing.T) {
+	e := Exponential{Rate: 1}
+	d := NewNumericInversion(nil, e.CDF, e.PDF, 1, 2, 1e-12)
+	for p := 0.0; p <= 1; p += 0.01 {
+		want := 1 - math.Log1p(-p*(1-math.Exp(-1)))
+		if x := d.Quantile(p); math.Abs(x-want) > 1e-8 {
+			t.Errorf("Quantile(%g) = %g, want %g", p, x, want)
+		}
+	}
+}
+
+func TestNumericInversionPanics(t *testing.T) {
+	e := Exponential{Rate: 1}
+	cases := map[string]func(){
+		"interval":      func() { NewNumericInversion(nil, e.CDF, e.PDF, 2, 1, 1e-10) },
+		"zero tol":      func() { NewNumericInversion(nil, e.CDF, e.PDF, 0, 1, 0) },
+		"negative tol":  func() { NewNumericInversion(nil, e.CDF, e.PDF, 0, 1, -1) },
+		"flat CDF":      func() { NewNumericInversion(nil, e.CDF, e.PDF, -2, -1, 1e-10) },
+		"infinite tail": func() { NewNumericInversion(nil, e.CDF, e.PDF, 0, math.Inf(1), 1e-10) },
+	}
+	for name, f := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestRejection(t *testing.T) {
+	// Half-normal from exponential proposals.
+	src := CryptoSeeded(NewMT64(), mt64N)
+	target := func(x float64) float64 {
+		if x < 0 {
+			return 0
+		}
+		return math.Sqrt(2/math.Pi) * math.Exp(-x*x/2)
+	}
+	d := NewRejection(src, target, NewExponential(src, 1), nil, math.Sqrt(2*math.E/math.Pi))
+	var m float64
+	n := 1 << 18
+	for i := 0; i < n; i++ {
+		x := d.Next()
+		if x < 0 {
+			t.Fatalf("variate %g out of range", x)
+		}
+		m += x
+	}
+	m /= float64(n)
+	if want := math.Sqrt(2 / math.Pi); math.Abs(m-want) > 0.01 {
+		t.Errorf("sample mean %g, want %g", m, want)
+	}
+}
+
+func TestRejectionNoPDF(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("NewRejection without a proposal PDF did not panic")
+		}
+	}()
+	NewRejection(nil, math.Exp, InverseTransform{}, nil, 1)
+}
+
+func BenchmarkNumericInversion(b *testing.B) {
+	nd := Normal{Mu: 0, Sigma: 1}
+	d := NewNumericInversion(CryptoSeeded(NewMT64(), mt64N), nd.CDF, nd.PDF, -8, 8, 1e-10)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = d.Next()
+	}
+}
@@ -0,0 +1,58 @@
+package mc
+
+import "github.com/zephyrtronium/crazy"
+
+// Antithetic produces antithetic pairs of variates from a distribution which
+// is symmetric about Center. Calls to Next alternate between a fresh variate x
+// from Dist and its reflection 2*Center - x.
+//
+// Antithetic holds the pending reflection, so it must be used through a
+// pointer, and it is not safe for concurrent use.
+type Antithetic struct {
+	// Dist is the underlying distribution.
+	Dist crazy.Distribution
+	// Center is the point of symmetry of Dist.
+	Center float64
+
+	x       float64
+	pending bool
+}
+
+// NewAntitheticUniform creates an antithetic sampler producing pairs u and
+// 1-u, where u is uniform in [0, 1). Note that the reflected variates lie in
+// (0, 1].
+func NewAntitheticUniform(src crazy.Source) *Antithetic {
+	return &Antithetic{Dist: crazy.Uniform0_1{Source: src}, Center: 0.5}
+}
+
+// NewAntitheticNormal creates an antithetic sampler producing pairs x and
+// 2*mean - x, where x is normally distributed.
+func NewAntitheticNormal(src crazy.Source, mean, stddev float64) *Antithetic {
+	return &Antithetic{Dist: crazy.NewNormal(src, mean, stddev), Center: mean}
+}
+
+// Next generates a variate. Every second call returns the reflection of the
+// previous one.
+func (a *Antithetic) Next() float64 {
+	if a.pending {
+		a.pending = false
+		return 2*a.Center - a.x
+	}
+	a.x = a.Dist.Next()
+	a.pending = true
+	return a.x
+}
+
+// Pair generates a fresh variate and its reflection, discarding any pending
+// reflection.
+func (a *Antithetic) Pair() (x, y float64) {
+	a.pending = false
+	x = a.Dist.Next()
+	return x, 2*a.Center - x
+}
+
+// Reset discards any pending reflection, so that the next call to Next draws
+// a fresh variate.
+func (a *Antithetic) Reset() {
+	a.pending = false
+}
@@ -0,0 +1,47 @@
+package mc
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+func TestAntitheticUniform(t *testing.T) {
+	a := NewAntitheticUniform(crazy.CryptoSeeded(crazy.NewXoshiro(), 32))
+	for i := 0; i < 1000; i++ {
+		x, y := a.Next(), a.Next()
+		if x < 0 || x >= 1 || x+y != 1 {
+			t.Fatalf("pair %d is %v, %v", i, x, y)
+		}
+	}
+}
+
+func TestAntitheticNormal(t *testing.T) {
+	a := NewAntitheticNormal(crazy.CryptoSeeded(crazy.NewXoshiro(), 32), 3, 2)
+	var s float64
+	for i := 0; i < 1000; i++ {
+		x, y := a.Pair()
+		if math.Abs(x+y-6) > 1e-12 {
+			t.Fatalf("pair %d is %v, %v", i, x, y)
+		}
+		s += x + y
+	}
+	// Pairs about the mean make the sample mean exact up to rounding.
+	if math.Abs(s/2000-3) > 1e-12 {
+		t.Errorf("mean is %v, want 3", s/2000)
+	}
+}
+
+func TestAntitheticReset(t *testing.T) {
+	a := NewAntitheticUniform(crazy.CryptoSeeded(crazy.NewXoshiro(), 32))
+	x := a.Next()
+	a.Reset()
+	y := a.Next()
+	if x+y == 1 {
+		t.Errorf("Reset did not discard reflection of %v", x)
+	}
+	if z := a.Next(); y+z != 1 {
+		t.Errorf("pair after Reset is %v, %v", y, z)
+	}
+}
@@ -0,0 +1,88 @@
+package mc
+
+import "math"
+
+// ControlVariate estimates the mean of a quantity Y using a control variate C
+// whose mean is known exactly. Observations are accumulated online, and the
+// estimate uses the variance-minimizing coefficient estimated from the same
+// observations:
+//
+//	Y - Beta*(C - Mean)
+//
+// where Beta = Cov(Y, C) / Var(C). The zero value is not usable; create
+// estimators with NewControlVariate.
+type ControlVariate struct {
+	// Mean is the known mean of the control.
+	Mean float64
+
+	n      int64
+	my, mc float64
+	// syy, scc, syc are sums of squared and cross deviations.
+	syy, scc, syc float64
+}
+
+// NewControlVariate creates a control variate estimator for a control with the
+// given known mean.
+func NewControlVariate(mean float64) *ControlVariate {
+	return &ControlVariate{Mean: mean}
+}
+
+// Add records an observation y of the quantity of interest along with the
+// control c observed on the same draw.
+func (cv *ControlVariate) Add(y, c float64) {
+	cv.n++
+	n := float64(cv.n)
+	dy, dc := y-cv.my, c-cv.mc
+	cv.my += dy / n
+	cv.mc += dc / n
+	cv.syy += dy * (y - cv.my)
+	cv.scc += dc * (c - cv.mc)
+	cv.syc += dy * (c - cv.mc)
+}
+
+// N returns the number of observations.
+func (cv *ControlVariate) N() int64 {
+	return cv.n
+}
+
+// Beta returns the estimated optimal control coefficient. It is zero if the
+// control has not varied.
+func (cv *ControlVariate) Beta() float64 {
+	if cv.scc == 0 {
+		return 0
+	}
+	return cv.syc / cv.scc
+}
+
+// Estimate returns the controlled estimate of the mean of Y.
+func (cv *ControlVariate) Estimate() float64 {
+	return cv.my - cv.Beta()*(cv.mc-cv.Mean)
+}
+
+// Raw returns the uncontrolled sample mean of Y.
+func (cv *ControlVariate) Raw() float64 {
+	return cv.my
+}
+
+// StdErr returns the estimated standard error of Estimate, computed from the
+// residual variance of Y after regression on C.
+func (cv *ControlVariate) StdErr() float64 {
+	if cv.n < 3 {
+		return math.Inf(1)
+	}
+	r := cv.syy - cv.Beta()*cv.syc
+	if r < 0 {
+		r = 0
+	}
+	// One degree of freedom is lost to each of the mean and Beta.
+	return math.Sqrt(r / float64(cv.n-2) / float64(cv.n))
+}
+
+// Correlation returns the sample correlation between Y and C. The variance
+// reduction relative to the raw mean is approximately 1 - Correlation()**2.
+func (cv *ControlVariate) Correlation() float64 {
+	if cv.syy == 0 || cv.scc == 0 {
+		return 0
+	}
+	return cv.syc / math.Sqrt(cv.syy*cv.scc)
+}
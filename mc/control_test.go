@@ -0,0 +1,35 @@
+package mc
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+func TestControlVariate(t *testing.T) {
+	// Estimate E[exp(U)] = e - 1 using U itself, with mean 1/2, as control.
+	u := crazy.Uniform0_1{Source: crazy.CryptoSeeded(crazy.NewXoshiro(), 32)}
+	const n = 10000
+	cv := NewControlVariate(0.5)
+	for i := 0; i < n; i++ {
+		x := u.Next()
+		cv.Add(math.Exp(x), x)
+	}
+	want := math.E - 1
+	if got := cv.Estimate(); math.Abs(got-want) > 5*cv.StdErr() {
+		t.Errorf("estimate is %v±%v, want %v", got, cv.StdErr(), want)
+	}
+	// Var(exp(U)) ≈ 0.242; the controlled residual variance is ≈ 0.0039.
+	raw := math.Sqrt(0.242 / n)
+	if se := cv.StdErr(); se > raw/5 {
+		t.Errorf("standard error %v not much smaller than raw %v", se, raw)
+	}
+	if r := cv.Correlation(); r < 0.98 {
+		t.Errorf("correlation is %v, want about 0.99", r)
+	}
+	// Optimal beta is Cov(exp(U), U)/Var(U) = 12*(3-e)/2 ≈ 1.69.
+	if b := cv.Beta(); math.Abs(b-6*(3-math.E)) > 0.05 {
+		t.Errorf("beta is %v, want %v", b, 6*(3-math.E))
+	}
+}
@@ -0,0 +1,47 @@
+package mc
+
+import "github.com/zephyrtronium/crazy"
+
+// CRN manages common random numbers. Each call to Stream returns an
+// independent copy of the same generator state, so scenarios that each take
+// their own stream consume identical random numbers regardless of how many
+// each one uses. Advance then moves to a fresh state for the next replication.
+//
+// A typical replication loop looks like:
+//
+//	crn := mc.NewCRN(crazy.CryptoSeeded(crazy.NewXoshiro(), 32).(crazy.Copier))
+//	for r := 0; r < reps; r++ {
+//		a := simulate(paramsA, crazy.RNG{Source: crn.Stream()})
+//		b := simulate(paramsB, crazy.RNG{Source: crn.Stream()})
+//		diff.Add(a - b)
+//		crn.Advance()
+//	}
+type CRN struct {
+	base crazy.Copier
+}
+
+// NewCRN creates a common random number manager whose streams start from the
+// current state of src. src itself is never used to generate values handed to
+// scenarios, but Advance modifies it.
+func NewCRN(src crazy.Copier) *CRN {
+	return &CRN{base: src}
+}
+
+// Stream returns a copy of the current state. Every stream returned between
+// two calls to Advance produces the same sequence.
+func (c *CRN) Stream() crazy.Copier {
+	return c.base.Copy()
+}
+
+// Advance moves to a new state whose streams do not overlap those of previous
+// states. If the generator is a crazy.Jumper, Advance jumps it; otherwise, it
+// reseeds the generator using its own output.
+func (c *CRN) Advance() {
+	if j, ok := c.base.(crazy.Jumper); ok {
+		j.Jump()
+		return
+	}
+	var iv [32]byte
+	c.base.Read(iv[:])
+	c.base.SeedIV(iv[:])
+}
@@ -0,0 +1,33 @@
+package mc
+
+import (
+	"testing"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+func TestCRNStreams(t *testing.T) {
+	for _, src := range []crazy.Copier{crazy.NewXoshiro(), crazy.NewMT64()} {
+		src.SeedIV([]byte("common random numbers"))
+		crn := NewCRN(src)
+		for r := 0; r < 3; r++ {
+			a := crazy.RNG{Source: crn.Stream()}
+			// Consume the first stream partially before reading the second.
+			first := make([]uint64, 10)
+			for i := range first {
+				first[i] = a.Uint64()
+			}
+			b := crazy.RNG{Source: crn.Stream()}
+			for i, v := range first {
+				if w := b.Uint64(); w != v {
+					t.Fatalf("%T replication %d value %d differs: %x vs %x", src, r, i, v, w)
+				}
+			}
+			crn.Advance()
+			c := crazy.RNG{Source: crn.Stream()}
+			if c.Uint64() == first[0] {
+				t.Fatalf("%T replication %d: Advance did not change stream", src, r)
+			}
+		}
+	}
+}
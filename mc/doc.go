@@ -0,0 +1,17 @@
+/*
+Package mc provides variance reduction helpers for Monte Carlo estimation with
+crazy Sources.
+
+Antithetic pairs each variate with its reflection about the center of a
+symmetric distribution, so that errors in the two halves of the pair tend to
+cancel. ControlVariate corrects an estimate using a correlated quantity whose
+mean is known exactly. CRN manages common random numbers, giving each of
+several scenarios an identical copy of the same stream so that differences
+between them are not obscured by sampling noise.
+
+Estimator accumulates samples online and reports moments, standard errors, and
+confidence intervals. Integrate and Integrator estimate integrals over the unit
+hypercube using plain Monte Carlo, randomized quasi-Monte Carlo, or Latin
+hypercube sampling, optionally stopping once a target error is reached.
+*/
+package mc
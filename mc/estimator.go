@@ -0,0 +1,119 @@
+package mc
+
+import (
+	"math"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+// Estimator accumulates samples online and reports their mean, variance, and
+// higher moments along with the standard error of the mean. The updates are
+// those of Welford extended to third and fourth moments as in Pébay,
+// "Formulas for robust, one-pass parallel computation of covariances and
+// arbitrary-order statistical moments," Sandia Report SAND2008-6212, 2008. The
+// zero value is an empty estimator ready to use.
+type Estimator struct {
+	n    int64
+	mean float64
+	// m2, m3, m4 are sums of powers of deviations from the mean.
+	m2, m3, m4 float64
+}
+
+// Add records a sample.
+func (e *Estimator) Add(x float64) {
+	n1 := float64(e.n)
+	e.n++
+	n := float64(e.n)
+	d := x - e.mean
+	dn := d / n
+	dn2 := dn * dn
+	t := d * dn * n1
+	e.mean += dn
+	e.m4 += t*dn2*(n*n-3*n+3) + 6*dn2*e.m2 - 4*dn*e.m3
+	e.m3 += t*dn*(n-2) - 3*dn*e.m2
+	e.m2 += t
+}
+
+// Merge adds all samples recorded by o to e, as if they had been added to e
+// directly. This allows estimators accumulated in parallel to be combined.
+func (e *Estimator) Merge(o *Estimator) {
+	if o.n == 0 {
+		return
+	}
+	if e.n == 0 {
+		*e = *o
+		return
+	}
+	na, nb := float64(e.n), float64(o.n)
+	n := na + nb
+	d := o.mean - e.mean
+	d2 := d * d
+	e.m4 += o.m4 + d2*d2*na*nb*(na*na-na*nb+nb*nb)/(n*n*n) +
+		6*d2*(na*na*o.m2+nb*nb*e.m2)/(n*n) + 4*d*(na*o.m3-nb*e.m3)/n
+	e.m3 += o.m3 + d2*d*na*nb*(na-nb)/(n*n) + 3*d*(na*o.m2-nb*e.m2)/n
+	e.m2 += o.m2 + d2*na*nb/n
+	e.mean += d * nb / n
+	e.n += o.n
+}
+
+// Reset discards all samples.
+func (e *Estimator) Reset() {
+	*e = Estimator{}
+}
+
+// N returns the number of samples.
+func (e *Estimator) N() int64 {
+	return e.n
+}
+
+// Mean returns the sample mean, or NaN if there are no samples.
+func (e *Estimator) Mean() float64 {
+	if e.n == 0 {
+		return math.NaN()
+	}
+	return e.mean
+}
+
+// Variance returns the unbiased sample variance, or NaN if there are fewer
+// than two samples.
+func (e *Estimator) Variance() float64 {
+	if e.n < 2 {
+		return math.NaN()
+	}
+	return e.m2 / float64(e.n-1)
+}
+
+// StdDev returns the sample standard deviation.
+func (e *Estimator) StdDev() float64 {
+	return math.Sqrt(e.Variance())
+}
+
+// StdErr returns the estimated standard error of the mean.
+func (e *Estimator) StdErr() float64 {
+	return math.Sqrt(e.Variance() / float64(e.n))
+}
+
+// Skewness returns the sample skewness.
+func (e *Estimator) Skewness() float64 {
+	if e.n < 2 {
+		return math.NaN()
+	}
+	return math.Sqrt(float64(e.n)) * e.m3 / math.Pow(e.m2, 1.5)
+}
+
+// Kurtosis returns the sample excess kurtosis, which is zero for a normal
+// distribution.
+func (e *Estimator) Kurtosis() float64 {
+	if e.n < 2 {
+		return math.NaN()
+	}
+	return float64(e.n)*e.m4/(e.m2*e.m2) - 3
+}
+
+// CI returns a confidence interval for the mean at the given level, e.g. 0.95,
+// using the normal approximation to the distribution of the sample mean.
+func (e *Estimator) CI(level float64) (lo, hi float64) {
+	z := crazy.Normal{Mu: 0, Sigma: 1}.Quantile(0.5 + 0.5*level)
+	h := z * e.StdErr()
+	return e.mean - h, e.mean + h
+}
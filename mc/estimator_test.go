@@ -0,0 +1,100 @@
+package mc
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+func TestEstimatorMoments(t *testing.T) {
+	xs := []float64{2, 4, 4, 4, 5, 5, 7, 9, 1e3}
+	var e Estimator
+	var mean float64
+	for _, x := range xs {
+		e.Add(x)
+		mean += x / float64(len(xs))
+	}
+	var m2, m3, m4 float64
+	for _, x := range xs {
+		d := x - mean
+		m2 += d * d
+		m3 += d * d * d
+		m4 += d * d * d * d
+	}
+	n := float64(len(xs))
+	cases := []struct {
+		name      string
+		got, want float64
+	}{
+		{"mean", e.Mean(), mean},
+		{"variance", e.Variance(), m2 / (n - 1)},
+		{"skewness", e.Skewness(), math.Sqrt(n) * m3 / math.Pow(m2, 1.5)},
+		{"kurtosis", e.Kurtosis(), n*m4/(m2*m2) - 3},
+	}
+	for _, c := range cases {
+		if math.Abs(c.got-c.want) > 1e-10*math.Abs(c.want) {
+			t.Errorf("%s is %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestEstimatorMerge(t *testing.T) {
+	d := crazy.NewNormal(crazy.CryptoSeeded(crazy.NewXoshiro(), 32), 5, 3)
+	var all, a, b Estimator
+	for i := 0; i < 1000; i++ {
+		x := d.Next()
+		all.Add(x)
+		if i < 300 {
+			a.Add(x)
+		} else {
+			b.Add(x)
+		}
+	}
+	a.Merge(&b)
+	if a.N() != all.N() {
+		t.Fatalf("merged N is %d, want %d", a.N(), all.N())
+	}
+	pairs := [][2]float64{
+		{a.Mean(), all.Mean()},
+		{a.Variance(), all.Variance()},
+		{a.Skewness(), all.Skewness()},
+		{a.Kurtosis(), all.Kurtosis()},
+	}
+	for i, p := range pairs {
+		if math.Abs(p[0]-p[1]) > 1e-9*math.Max(1, math.Abs(p[1])) {
+			t.Errorf("moment %d of merged is %v, want %v", i+1, p[0], p[1])
+		}
+	}
+	var empty Estimator
+	empty.Merge(&all)
+	if empty != all {
+		t.Errorf("merge into empty gave %+v, want %+v", empty, all)
+	}
+}
+
+func TestEstimatorCI(t *testing.T) {
+	d := crazy.NewNormal(crazy.CryptoSeeded(crazy.NewXoshiro(), 32), 0, 1)
+	const reps = 1000
+	cover := 0
+	for r := 0; r < reps; r++ {
+		var e Estimator
+		for i := 0; i < 100; i++ {
+			e.Add(d.Next())
+		}
+		if lo, hi := e.CI(0.9); lo <= 0 && 0 <= hi {
+			cover++
+		}
+	}
+	// Binomial(1000, 0.9) has standard deviation about 9.5.
+	if cover < 860 || cover > 940 {
+		t.Errorf("90%% interval covered %d of %d times", cover, reps)
+	}
+}
+
+func TestEstimatorEmpty(t *testing.T) {
+	var e Estimator
+	if !math.IsNaN(e.Mean()) || !math.IsNaN(e.Variance()) {
+		t.Errorf("empty estimator has mean %v, variance %v", e.Mean(), e.Variance())
+	}
+}
@@ -0,0 +1,138 @@
+package mc
+
+import (
+	"github.com/zephyrtronium/crazy"
+	"github.com/zephyrtronium/crazy/qmc"
+)
+
+// Strategy selects how an Integrator chooses points.
+type Strategy int
+
+const (
+	// PlainMC uses independent uniform points. The error estimate comes
+	// from the spread of the individual evaluations.
+	PlainMC Strategy = iota
+	// QuasiMC uses independently scrambled Sobol sequences. Sobol needs
+	// direction numbers for every dimension, and only qmc.SobolDims are
+	// built in; in more dimensions, QuasiMC uses scrambled Halton sequences
+	// instead unless Integrator.Directions supplies enough. Use
+	// Integrator.Sobol to check which applies. Each randomization gives one
+	// estimate, and the error estimate comes from their spread.
+	QuasiMC
+	// Stratified uses independent Latin hypercube samples, each giving one
+	// estimate.
+	Stratified
+)
+
+// Integrator estimates integrals over the unit hypercube [0, 1)^Dims.
+type Integrator struct {
+	// Src is the source of randomness.
+	Src crazy.Source
+	// Dims is the dimension of the domain of integration.
+	Dims int
+	// Strategy selects how points are chosen.
+	Strategy Strategy
+	// Batch is the number of points evaluated between checks of the error.
+	// For QuasiMC and Stratified, it is the number of points in each
+	// randomization. If zero, 1024 is used.
+	Batch int
+	// MaxN is the maximum number of evaluations. If zero, 1<<20 is used.
+	MaxN int
+	// Directions, if not nil, replaces the built-in Sobol direction numbers
+	// for QuasiMC, e.g. with the full table of Joe and Kuo read by
+	// qmc.ReadDirectionNumbers, so that Sobol sequences can be used in more
+	// than qmc.SobolDims dimensions.
+	Directions []qmc.DirectionNumbers
+	// Tol is the target standard error. Integration stops once the estimated
+	// standard error is at most Tol, or after MaxN evaluations. If zero,
+	// integration always uses MaxN evaluations, rounded up to a whole batch.
+	Tol float64
+}
+
+// Sobol reports whether QuasiMC uses Sobol sequences for in.Dims dimensions.
+// If not, it uses Halton sequences, which have noticeably worse uniformity in
+// high dimensions.
+func (in Integrator) Sobol() bool {
+	if in.Directions != nil {
+		return in.Dims <= len(in.Directions)+1
+	}
+	return in.Dims <= qmc.SobolDims
+}
+
+// Integrate estimates the integral of f over [0, 1)^dims by plain Monte Carlo
+// with n points drawn from src. The mean of the result is the estimate, and
+// its StdErr is the standard error. f must not retain its argument.
+func Integrate(f func(x []float64) float64, dims, n int, src crazy.Source) *Estimator {
+	in := Integrator{Src: src, Dims: dims, Strategy: PlainMC, MaxN: n}
+	return in.Integrate(f)
+}
+
+// Integrate estimates the integral of f. The mean of the result is the
+// estimate, and its StdErr is the standard error. For PlainMC, the result's
+// samples are individual evaluations of f; otherwise, they are the estimates
+// from each randomization. f must not retain its argument.
+func (in Integrator) Integrate(f func(x []float64) float64) *Estimator {
+	batch, maxn := in.Batch, in.MaxN
+	if batch <= 0 {
+		batch = 1024
+	}
+	if maxn <= 0 {
+		maxn = 1 << 20
+	}
+	var e Estimator
+	x := make([]float64, in.Dims)
+	rng := crazy.RNG{Source: in.Src}
+	u := crazy.Uniform0_1{Source: in.Src}
+	for n := 0; n < maxn; {
+		switch in.Strategy {
+		case PlainMC:
+			k := batch
+			if k > maxn-n {
+				k = maxn - n
+			}
+			for i := 0; i < k; i++ {
+				for j := range x {
+					x[j] = u.Next()
+				}
+				e.Add(f(x))
+			}
+			n += k
+		case QuasiMC:
+			var s qmc.Sequence
+			switch {
+			case in.Sobol() && in.Directions != nil:
+				q := qmc.NewSobolFrom(in.Directions, in.Dims)
+				q.Scramble(in.Src)
+				s = q
+			case in.Sobol():
+				q := qmc.NewSobol(in.Dims)
+				q.Scramble(in.Src)
+				s = q
+			default:
+				q := qmc.NewHalton(in.Dims)
+				q.Scramble(in.Src)
+				s = q
+			}
+			var b Estimator
+			for i := 0; i < batch; i++ {
+				s.NextVector(x)
+				b.Add(f(x))
+			}
+			e.Add(b.Mean())
+			n += batch
+		case Stratified:
+			var b Estimator
+			for _, p := range qmc.LatinHypercube(batch, in.Dims, rng) {
+				b.Add(f(p))
+			}
+			e.Add(b.Mean())
+			n += batch
+		default:
+			panic("mc: unknown integration strategy")
+		}
+		if in.Tol > 0 && e.N() >= 2 && e.StdErr() <= in.Tol {
+			break
+		}
+	}
+	return &e
+}
@@ -0,0 +1,115 @@
+package mc
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zephyrtronium/crazy"
+	"github.com/zephyrtronium/crazy/qmc"
+)
+
+// product is a smooth test integrand with integral 1 over [0, 1)^d.
+func product(x []float64) float64 {
+	p := 1.0
+	for _, v := range x {
+		p *= 1.5 * math.Sqrt(v)
+	}
+	return p
+}
+
+// head is product in the first four dimensions, for integrals in many
+// dimensions that still have small variance.
+func head(x []float64) float64 {
+	return product(x[:4])
+}
+
+func TestIntegrate(t *testing.T) {
+	e := Integrate(product, 4, 100000, crazy.CryptoSeeded(crazy.NewXoshiro(), 32))
+	if e.N() != 100000 {
+		t.Errorf("used %d points, want 100000", e.N())
+	}
+	if math.Abs(e.Mean()-1) > 5*e.StdErr() {
+		t.Errorf("integral is %v±%v, want 1", e.Mean(), e.StdErr())
+	}
+}
+
+func TestIntegratorStrategies(t *testing.T) {
+	for _, s := range []struct {
+		name string
+		s    Strategy
+		dims int
+		f    func([]float64) float64
+	}{
+		{"plain", PlainMC, 4, product},
+		{"sobol", QuasiMC, 4, product},
+		{"sobol-high", QuasiMC, 30, product},
+		{"halton", QuasiMC, qmc.SobolDims + 1, head},
+		{"lhs", Stratified, 4, product},
+	} {
+		t.Run(s.name, func(t *testing.T) {
+			in := Integrator{
+				Src:      crazy.CryptoSeeded(crazy.NewXoshiro(), 32),
+				Dims:     s.dims,
+				Strategy: s.s,
+				MaxN:     1 << 16,
+			}
+			e := in.Integrate(s.f)
+			if math.Abs(e.Mean()-1) > 5*e.StdErr() {
+				t.Errorf("integral is %v±%v, want 1", e.Mean(), e.StdErr())
+			}
+		})
+	}
+}
+
+func TestIntegratorQMCError(t *testing.T) {
+	src := crazy.CryptoSeeded(crazy.NewXoshiro(), 32)
+	plain := Integrator{Src: src, Dims: 4, Strategy: PlainMC, MaxN: 1 << 16}.Integrate(product)
+	quasi := Integrator{Src: src, Dims: 4, Strategy: QuasiMC, MaxN: 1 << 16}.Integrate(product)
+	if quasi.StdErr() > plain.StdErr()/10 {
+		t.Errorf("QMC error %v not much smaller than MC error %v", quasi.StdErr(), plain.StdErr())
+	}
+}
+
+func TestIntegratorTol(t *testing.T) {
+	in := Integrator{
+		Src:      crazy.CryptoSeeded(crazy.NewXoshiro(), 32),
+		Dims:     2,
+		Strategy: PlainMC,
+		Batch:    100,
+		Tol:      0.01,
+	}
+	e := in.Integrate(product)
+	// The variance of the integrand is (9/8)**2 - 1 ≈ 0.27, so about 2700
+	// points are needed.
+	if e.StdErr() > 0.01 || e.N() > 5000 {
+		t.Errorf("stopped after %d points with error %v", e.N(), e.StdErr())
+	}
+}
+
+func TestIntegratorSobol(t *testing.T) {
+	if !(Integrator{Dims: qmc.SobolDims}).Sobol() {
+		t.Errorf("built-in directions do not cover %d dimensions", qmc.SobolDims)
+	}
+	if (Integrator{Dims: qmc.SobolDims + 1}).Sobol() {
+		t.Errorf("built-in directions claim to cover %d dimensions", qmc.SobolDims+1)
+	}
+	// Supplied direction numbers extend the range of Sobol sequences. These
+	// repeat one polynomial, which is poor but valid.
+	dirs := make([]qmc.DirectionNumbers, qmc.SobolDims+8)
+	for i := range dirs {
+		dirs[i] = qmc.DirectionNumbers{S: 1, A: 0, M: []uint32{1}}
+	}
+	in := Integrator{
+		Src:        crazy.CryptoSeeded(crazy.NewXoshiro(), 32),
+		Dims:       qmc.SobolDims + 9,
+		Strategy:   QuasiMC,
+		Directions: dirs,
+		MaxN:       1 << 14,
+	}
+	if !in.Sobol() {
+		t.Fatalf("supplied directions do not cover %d dimensions", in.Dims)
+	}
+	if e := in.Integrate(head); math.Abs(e.Mean()-1) > 5*e.StdErr() {
+		t.Errorf("integral is %v±%v, want 1", e.Mean(), e.StdErr())
+	}
+}
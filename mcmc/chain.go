@@ -0,0 +1,133 @@
+package mcmc
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+// Target is the logarithm of a (possibly unnormalized) density over R^n.
+type Target func(x []float64) float64
+
+// A Kernel is a Markov transition kernel that leaves a target distribution
+// invariant.
+type Kernel interface {
+	// Step advances x in place, drawing randomness from src. lp is
+	// target(x) on entry, and Step returns target(x) for the new x.
+	Step(src crazy.Source, target Target, x []float64, lp float64) float64
+}
+
+// Chain is a Markov chain over R^n.
+type Chain struct {
+	// Src is the source of all randomness used by the chain.
+	Src crazy.Source
+	// Target is the log density that the chain samples.
+	Target Target
+	// Kernel advances the chain.
+	Kernel Kernel
+	// X is the current position of the chain.
+	X []float64
+	// LogP is Target(X).
+	LogP float64
+	// N is the number of steps taken.
+	N int64
+}
+
+// NewChain creates a chain starting at a copy of x0.
+func NewChain(src crazy.Source, target Target, kernel Kernel, x0 []float64) *Chain {
+	x := append([]float64(nil), x0...)
+	return &Chain{
+		Src:    src,
+		Target: target,
+		Kernel: kernel,
+		X:      x,
+		LogP:   target(x),
+	}
+}
+
+// NewChains creates one chain for each starting point in x0. Each chain
+// draws from a separate stream jumped from src, which must also implement
+// crazy.Copier, and uses its own kernel produced by kernel.
+func NewChains(src crazy.Jumper, target Target, kernel func() Kernel, x0 [][]float64) []*Chain {
+	s := crazy.Streams(src, len(x0))
+	c := make([]*Chain, len(x0))
+	for i := range c {
+		c[i] = NewChain(s[i], target, kernel(), x0[i])
+	}
+	return c
+}
+
+// Step advances the chain once.
+func (c *Chain) Step() {
+	c.LogP = c.Kernel.Step(c.Src, c.Target, c.X, c.LogP)
+	c.N++
+}
+
+// Run advances the chain n times, calling visit with the position after each
+// step if visit is not nil. visit must not retain x.
+func (c *Chain) Run(n int, visit func(x []float64)) {
+	for i := 0; i < n; i++ {
+		c.Step()
+		if visit != nil {
+			visit(c.X)
+		}
+	}
+}
+
+// ErrNotSaver is returned when saving or restoring a chain whose Source is
+// not a crazy.Saver.
+var ErrNotSaver = errors.New("mcmc: source is not a crazy.Saver")
+
+// Save writes the state of the chain's Source followed by the step count and
+// position. The kernel's state, such as acceptance counts, is not saved.
+func (c *Chain) Save(into io.Writer) (n int, err error) {
+	s, ok := c.Src.(crazy.Saver)
+	if !ok {
+		return 0, ErrNotSaver
+	}
+	if n, err = s.Save(into); err != nil {
+		return n, err
+	}
+	p := make([]byte, 12+8*len(c.X))
+	binary.LittleEndian.PutUint64(p, uint64(c.N))
+	binary.LittleEndian.PutUint32(p[8:], uint32(len(c.X)))
+	for i, v := range c.X {
+		binary.LittleEndian.PutUint64(p[12+8*i:], math.Float64bits(v))
+	}
+	k, err := into.Write(p)
+	return n + k, err
+}
+
+// Restore loads a state written by Save. The chain's Source must be the same
+// type of generator as the one that was saved.
+func (c *Chain) Restore(from io.Reader) (n int, err error) {
+	s, ok := c.Src.(crazy.Saver)
+	if !ok {
+		return 0, ErrNotSaver
+	}
+	if n, err = s.Restore(from); err != nil {
+		return n, err
+	}
+	p := [12]byte{}
+	k, err := io.ReadFull(from, p[:])
+	n += k
+	if err != nil {
+		return n, err
+	}
+	c.N = int64(binary.LittleEndian.Uint64(p[:]))
+	x := make([]byte, 8*binary.LittleEndian.Uint32(p[8:]))
+	k, err = io.ReadFull(from, x)
+	n += k
+	if err != nil {
+		return n, err
+	}
+	c.X = c.X[:0]
+	for i := 0; i < len(x); i += 8 {
+		c.X = append(c.X, math.Float64frombits(binary.LittleEndian.Uint64(x[i:])))
+	}
+	c.LogP = c.Target(c.X)
+	return n, nil
+}
@@ -0,0 +1,90 @@
+package mcmc
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+// stdNormal is the log density of a standard normal distribution in any
+// number of dimensions.
+func stdNormal(x []float64) float64 {
+	var s float64
+	for _, v := range x {
+		s += v * v
+	}
+	return -0.5 * s
+}
+
+// testMoments runs c for n steps after burning in and checks that each
+// coordinate has mean 0 and variance 1.
+func testMoments(t *testing.T, c *Chain, n int) {
+	t.Helper()
+	c.Run(1000, nil)
+	m := make([]float64, len(c.X))
+	s := make([]float64, len(c.X))
+	c.Run(n, func(x []float64) {
+		for i, v := range x {
+			m[i] += v
+			s[i] += v * v
+		}
+	})
+	for i := range m {
+		mean := m[i] / float64(n)
+		v := s[i]/float64(n) - mean*mean
+		if math.Abs(mean) > 0.05 {
+			t.Errorf("coordinate %d has mean %g", i, mean)
+		}
+		if math.Abs(v-1) > 0.1 {
+			t.Errorf("coordinate %d has variance %g", i, v)
+		}
+	}
+}
+
+func TestChainSave(t *testing.T) {
+	src := crazy.CryptoSeeded(crazy.NewXoshiro(), 32)
+	c := NewChain(src, stdNormal, &MH{Proposal: RandomWalk{Scale: 1}}, []float64{0, 0, 0})
+	c.Run(100, nil)
+	var b bytes.Buffer
+	if _, err := c.Save(&b); err != nil {
+		t.Fatal(err)
+	}
+	c.Run(100, nil)
+	want := append([]float64(nil), c.X...)
+	if _, err := c.Restore(&b); err != nil {
+		t.Fatal(err)
+	}
+	if c.N != 100 {
+		t.Errorf("restored step count %d, want 100", c.N)
+	}
+	c.Run(100, nil)
+	for i := range want {
+		if c.X[i] != want[i] {
+			t.Fatalf("restored chain diverged: %v, want %v", c.X, want)
+		}
+	}
+}
+
+func TestChainSaveNotSaver(t *testing.T) {
+	c := NewChain(crazy.XorCompose{}, stdNormal, Slice{Width: 1}, []float64{0})
+	if _, err := c.Save(new(bytes.Buffer)); err != ErrNotSaver {
+		t.Errorf("wrong error %v", err)
+	}
+}
+
+func TestNewChains(t *testing.T) {
+	src := crazy.CryptoSeeded(crazy.NewXoshiro(), 32).(*crazy.Xoshiro)
+	kernel := func() Kernel { return &MH{Proposal: RandomWalk{Scale: 1}} }
+	c := NewChains(src, stdNormal, kernel, [][]float64{{0}, {0}, {0}})
+	for _, v := range c {
+		v.Run(10, nil)
+	}
+	if c[0].X[0] == c[1].X[0] && c[1].X[0] == c[2].X[0] {
+		t.Errorf("chains on separate streams have the same position %g", c[0].X[0])
+	}
+	if c[0].Kernel == c[1].Kernel {
+		t.Error("chains share a kernel")
+	}
+}
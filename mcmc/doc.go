@@ -0,0 +1,15 @@
+/*
+Package mcmc provides Markov chain Monte Carlo samplers driven by crazy
+Sources.
+
+A Chain holds the current position of a Markov chain over R^n along with the
+Source that drives it and the Kernel that advances it. Kernels include
+Metropolis-Hastings with pluggable proposals, univariate slice sampling applied
+to each coordinate in turn, and Hamiltonian Monte Carlo.
+
+All randomness used by a chain comes from its Source, so a chain whose Source
+is a crazy.Saver can be checkpointed with Save and resumed exactly with
+Restore. Multiple chains can run in parallel on non-overlapping streams of a
+single jumpable generator using NewChains.
+*/
+package mcmc
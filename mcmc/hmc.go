@@ -0,0 +1,70 @@
+package mcmc
+
+import (
+	"math"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+// HMC is a Hamiltonian Monte Carlo kernel with identity mass matrix and
+// leapfrog integration. It must be used through a pointer, and a single HMC
+// must not be shared between chains running concurrently.
+type HMC struct {
+	// Grad writes the gradient of the target log density at x into dst.
+	Grad func(dst, x []float64)
+	// StepSize is the leapfrog step size.
+	StepSize float64
+	// Steps is the number of leapfrog steps per proposal.
+	Steps int
+	// Accepted and Proposed count moves.
+	Accepted, Proposed int64
+
+	q, p, g []float64
+}
+
+// Step performs one HMC update.
+func (h *HMC) Step(src crazy.Source, target Target, x []float64, lp float64) float64 {
+	n := len(x)
+	if cap(h.q) < n {
+		h.q, h.p, h.g = make([]float64, n), make([]float64, n), make([]float64, n)
+	}
+	q, p, g := h.q[:n], h.p[:n], h.g[:n]
+	copy(q, x)
+	d := crazy.NewNormal(src, 0, 1)
+	var k0 float64
+	for i := range p {
+		p[i] = d.Next()
+		k0 += p[i] * p[i]
+	}
+	eps := h.StepSize
+	h.Grad(g, q)
+	for l := 0; l < h.Steps; l++ {
+		for i := range p {
+			p[i] += 0.5 * eps * g[i]
+			q[i] += eps * p[i]
+		}
+		h.Grad(g, q)
+		for i := range p {
+			p[i] += 0.5 * eps * g[i]
+		}
+	}
+	var k1 float64
+	for _, v := range p {
+		k1 += v * v
+	}
+	lq := target(q)
+	h.Proposed++
+	// log acceptance = -H(q, p) + H(x, p0)
+	a := lq - lp - 0.5*(k1-k0)
+	if !math.IsNaN(a) && math.Log(crazy.Uniform0_1{Source: src}.Next()) < a {
+		copy(x, q)
+		h.Accepted++
+		return lq
+	}
+	return lp
+}
+
+// AcceptanceRate returns the fraction of proposals accepted so far.
+func (h *HMC) AcceptanceRate() float64 {
+	return float64(h.Accepted) / float64(h.Proposed)
+}
@@ -0,0 +1,24 @@
+package mcmc
+
+import (
+	"testing"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+func TestHMC(t *testing.T) {
+	src := crazy.CryptoSeeded(crazy.NewXoshiro(), 32)
+	k := &HMC{
+		Grad: func(dst, x []float64) {
+			for i, v := range x {
+				dst[i] = -v
+			}
+		},
+		StepSize: 0.3,
+		Steps:    7,
+	}
+	testMoments(t, NewChain(src, stdNormal, k, []float64{3, -3, 0}), 1<<16)
+	if r := k.AcceptanceRate(); r < 0.8 {
+		t.Errorf("acceptance rate %g", r)
+	}
+}
@@ -0,0 +1,80 @@
+package mcmc
+
+import (
+	"math"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+// A Proposal suggests candidate moves for Metropolis-Hastings.
+type Proposal interface {
+	// Propose writes a candidate based on x into y, drawing randomness from
+	// src, and returns the log Hastings correction log q(x|y) - log q(y|x),
+	// which is zero for symmetric proposals.
+	Propose(src crazy.Source, y, x []float64) float64
+}
+
+// MH is a Metropolis-Hastings kernel. It must be used through a pointer, and
+// a single MH must not be shared between chains running concurrently.
+type MH struct {
+	Proposal Proposal
+	// Accepted and Proposed count moves.
+	Accepted, Proposed int64
+
+	y []float64
+}
+
+// Step performs one Metropolis-Hastings update.
+func (m *MH) Step(src crazy.Source, target Target, x []float64, lp float64) float64 {
+	if cap(m.y) < len(x) {
+		m.y = make([]float64, len(x))
+	}
+	y := m.y[:len(x)]
+	c := m.Proposal.Propose(src, y, x)
+	ly := target(y)
+	m.Proposed++
+	if math.Log(crazy.Uniform0_1{Source: src}.Next()) < ly-lp+c {
+		copy(x, y)
+		m.Accepted++
+		return ly
+	}
+	return lp
+}
+
+// AcceptanceRate returns the fraction of proposals accepted so far.
+func (m *MH) AcceptanceRate() float64 {
+	return float64(m.Accepted) / float64(m.Proposed)
+}
+
+// RandomWalk proposes moves by adding independent normal variates with
+// standard deviation Scale to each coordinate.
+type RandomWalk struct {
+	Scale float64
+}
+
+// Propose proposes a random walk step. The correction is always zero.
+func (r RandomWalk) Propose(src crazy.Source, y, x []float64) float64 {
+	d := crazy.NewNormal(src, 0, r.Scale)
+	for i, v := range x {
+		y[i] = v + d.Next()
+	}
+	return 0
+}
+
+// Independent proposes each coordinate independently of the current position
+// according to Dist. Only the parameters of Dist are used; variates are drawn
+// by inverse transform from the chain's source.
+type Independent struct {
+	Dist crazy.Analytic
+}
+
+// Propose proposes an independent draw.
+func (p Independent) Propose(src crazy.Source, y, x []float64) float64 {
+	u := crazy.Uniform0_1{Source: src}
+	var c float64
+	for i, v := range x {
+		y[i] = p.Dist.Quantile(u.Next())
+		c += math.Log(p.Dist.PDF(v)) - math.Log(p.Dist.PDF(y[i]))
+	}
+	return c
+}
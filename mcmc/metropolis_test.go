@@ -0,0 +1,22 @@
+package mcmc
+
+import (
+	"testing"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+func TestRandomWalk(t *testing.T) {
+	src := crazy.CryptoSeeded(crazy.NewXoshiro(), 32)
+	k := &MH{Proposal: RandomWalk{Scale: 2.4}}
+	testMoments(t, NewChain(src, stdNormal, k, []float64{3, -3}), 1<<18)
+	if r := k.AcceptanceRate(); r < 0.2 || r > 0.6 {
+		t.Errorf("acceptance rate %g", r)
+	}
+}
+
+func TestIndependent(t *testing.T) {
+	src := crazy.CryptoSeeded(crazy.NewXoshiro(), 32)
+	k := &MH{Proposal: Independent{Dist: crazy.Normal{Mu: 0, Sigma: 1.5}}}
+	testMoments(t, NewChain(src, stdNormal, k, []float64{3, -3}), 1<<17)
+}
@@ -0,0 +1,69 @@
+package mcmc
+
+import (
+	"math"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+// Slice is a slice sampling kernel which updates each coordinate in turn
+// using the stepping out and shrinkage procedures of Neal, "Slice sampling,"
+// Annals of Statistics 31(3), 2003.
+type Slice struct {
+	// Width is the initial size of the interval around each coordinate.
+	Width float64
+	// MaxSteps limits the size of the interval to MaxSteps*Width. If it is
+	// zero, the interval grows without limit.
+	MaxSteps int
+}
+
+// Step performs one slice sampling update of each coordinate.
+func (s Slice) Step(src crazy.Source, target Target, x []float64, lp float64) float64 {
+	u := crazy.Uniform0_1{Source: src}
+	e := crazy.NewExponential(src, 1)
+	f := func(i int, v float64) float64 {
+		x[i] = v
+		return target(x)
+	}
+	for i, x0 := range x {
+		y := lp - e.Next()
+		l := x0 - s.Width*u.Next()
+		r := l + s.Width
+		if s.MaxSteps > 0 {
+			j := int(float64(s.MaxSteps) * u.Next())
+			k := s.MaxSteps - 1 - j
+			for ; j > 0 && f(i, l) > y; j-- {
+				l -= s.Width
+			}
+			for ; k > 0 && f(i, r) > y; k-- {
+				r += s.Width
+			}
+		} else {
+			for f(i, l) > y {
+				l -= s.Width
+			}
+			for f(i, r) > y {
+				r += s.Width
+			}
+		}
+		for {
+			v := l + (r-l)*u.Next()
+			if lv := f(i, v); lv > y {
+				lp = lv
+				break
+			}
+			if v < x0 {
+				l = v
+			} else {
+				r = v
+			}
+			if r-l <= math.Abs(x0)*1e-15 {
+				// The interval has collapsed onto x0, which must be in the
+				// slice; this only happens with a badly behaved target.
+				lp = f(i, x0)
+				break
+			}
+		}
+	}
+	return lp
+}
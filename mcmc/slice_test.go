@@ -0,0 +1,17 @@
+package mcmc
+
+import (
+	"testing"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+func TestSlice(t *testing.T) {
+	src := crazy.CryptoSeeded(crazy.NewXoshiro(), 32)
+	testMoments(t, NewChain(src, stdNormal, Slice{Width: 1, MaxSteps: 10}, []float64{3, -3}), 1<<17)
+}
+
+func TestSliceUnlimited(t *testing.T) {
+	src := crazy.CryptoSeeded(crazy.NewXoshiro(), 32)
+	testMoments(t, NewChain(src, stdNormal, Slice{Width: 0.1}, []float64{10}), 1<<17)
+}
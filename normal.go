@@ -3,10 +3,10 @@ package crazy
 import "math"
 
 // Normal adapts a Source to produce random numbers under a normal
-// distribution.
+// distribution. Mu is the mean and Sigma is the standard deviation.
 type Normal struct {
 	Source
-	Mean, StdDev float64
+	Mu, Sigma float64
 }
 
 // NewNormal creates a normal distribution drawing from the specified source
@@ -14,15 +14,50 @@ type Normal struct {
 func NewNormal(src Source, mean, stddev float64) Normal {
 	return Normal{
 		Source: src,
-		Mean:   mean,
-		StdDev: stddev,
+		Mu:     mean,
+		Sigma:  stddev,
 	}
 }
 
 // Next generates a normal variate.
 func (n Normal) Next() float64 {
 	x := normalZig.GenNext(n.Source)
-	return n.Mean + x*n.StdDev
+	return n.Mu + x*n.Sigma
+}
+
+// NextFull generates a normal variate whose tails beyond the ziggurat are
+// drawn using full-precision uniforms. It is as fast as Next except in the
+// rare tail case, where Next can produce no variates beyond about 13 standard
+// deviations.
+func (n Normal) NextFull() float64 {
+	x := normalZigFull.GenNext(n.Source)
+	return n.Mu + x*n.Sigma
+}
+
+// PDF evaluates the probability density function at x.
+func (n Normal) PDF(x float64) float64 {
+	z := (x - n.Mu) / n.Sigma
+	return math.Exp(-0.5*z*z) / (n.Sigma * math.Sqrt(2*math.Pi))
+}
+
+// CDF evaluates the cumulative distribution function at x.
+func (n Normal) CDF(x float64) float64 {
+	return 0.5 * math.Erfc(-(x-n.Mu)/(n.Sigma*math.Sqrt2))
+}
+
+// Quantile evaluates the inverse of the CDF at p.
+func (n Normal) Quantile(p float64) float64 {
+	return n.Mu - n.Sigma*math.Sqrt2*math.Erfcinv(2*p)
+}
+
+// Mean returns Mu.
+func (n Normal) Mean() float64 {
+	return n.Mu
+}
+
+// Variance returns Sigma squared.
+func (n Normal) Variance() float64 {
+	return n.Sigma * n.Sigma
 }
 
 func normalPDF(x float64) float64 {
@@ -37,6 +72,9 @@ func normalTail(src Source) float64 {
 		if y+y >= x*x {
 			return x + normalR
 		}
+		if failed(src) {
+			return math.NaN()
+		}
 	}
 }
 
@@ -9,3 +9,9 @@ func BenchmarkNormal(b *testing.B) {
 		_ = d.Next()
 	}
 }
+
+func TestNormalAnalytic(t *testing.T) {
+	d := NewNormal(CryptoSeeded(NewMT64(), mt64N), 3, 2)
+	testAnalytic(t, d, -5, 0, 1, 3, 4.5, 12)
+	testMoments(t, d, 1<<16)
+}
@@ -0,0 +1,74 @@
+package process
+
+import (
+	"math"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+// OU is an Ornstein-Uhlenbeck process, the solution of
+//
+//	dX = Theta*(Mu - X)*dt + Sigma*dW.
+//
+// Paths are sampled from the exact transition distribution, so there is no
+// discretization error regardless of the spacing of times.
+type OU struct {
+	crazy.Source
+	// X0 is the value at time zero.
+	X0 float64
+	// Theta is the rate of mean reversion. It must be positive.
+	Theta float64
+	// Mu is the long-term mean.
+	Mu float64
+	// Sigma is the volatility.
+	Sigma float64
+}
+
+// Path appends the values of the process at each of the times in t to dst and
+// returns the result. t must be nonnegative and nondecreasing.
+func (p OU) Path(dst, t []float64) []float64 {
+	z := crazy.NewNormal(p.Source, 0, 1)
+	x, s := p.X0, 0.0
+	for _, u := range t {
+		dt := u - s
+		// x - Mu decays by exp(-Theta*dt), and the variance of the noise
+		// accumulated over the step is Sigma²(1-exp(-2*Theta*dt))/(2*Theta).
+		d := math.Exp(-p.Theta * dt)
+		v := -math.Expm1(-2*p.Theta*dt) / (2 * p.Theta)
+		x = p.Mu + (x-p.Mu)*d + p.Sigma*math.Sqrt(v)*z.Next()
+		s = u
+		dst = append(dst, x)
+	}
+	return dst
+}
+
+// GBM is geometric Brownian motion, the solution of
+//
+//	dS = Mu*S*dt + Sigma*S*dW,
+//
+// sampled exactly as S(t) = S0*exp((Mu - Sigma²/2)*t + Sigma*W(t)).
+type GBM struct {
+	crazy.Source
+	// S0 is the value at time zero.
+	S0 float64
+	// Mu is the drift.
+	Mu float64
+	// Sigma is the volatility.
+	Sigma float64
+}
+
+// Path appends the values of the process at each of the times in t to dst and
+// returns the result. t must be nonnegative and nondecreasing.
+func (p GBM) Path(dst, t []float64) []float64 {
+	z := crazy.NewNormal(p.Source, 0, 1)
+	m := p.Mu - 0.5*p.Sigma*p.Sigma
+	// Accumulate the logarithm to avoid compounding rounding error.
+	x, s := 0.0, 0.0
+	for _, u := range t {
+		dt := u - s
+		x += m*dt + p.Sigma*math.Sqrt(dt)*z.Next()
+		s = u
+		dst = append(dst, p.S0*math.Exp(x))
+	}
+	return dst
+}
@@ -0,0 +1,56 @@
+package process
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+func TestOU(t *testing.T) {
+	p := OU{
+		Source: crazy.CryptoSeeded(crazy.NewXoshiro(), 32),
+		X0:     5,
+		Theta:  0.7,
+		Mu:     1,
+		Sigma:  0.5,
+	}
+	times := []float64{0.1, 1, 10}
+	const n = 10000
+	cols := make([][]float64, len(times))
+	var x []float64
+	for i := 0; i < n; i++ {
+		x = p.Path(x[:0], times)
+		for j, v := range x {
+			cols[j] = append(cols[j], v)
+		}
+	}
+	for j, u := range times {
+		mean := 1 + 4*math.Exp(-0.7*u)
+		variance := 0.25 * (1 - math.Exp(-1.4*u)) / 1.4
+		testMeanVar(t, "OU", cols[j], mean, variance)
+	}
+}
+
+func TestGBM(t *testing.T) {
+	p := GBM{
+		Source: crazy.CryptoSeeded(crazy.NewXoshiro(), 32),
+		S0:     100,
+		Mu:     0.05,
+		Sigma:  0.2,
+	}
+	times := []float64{0.5, 1, 2}
+	const n = 10000
+	cols := make([][]float64, len(times))
+	var x []float64
+	for i := 0; i < n; i++ {
+		x = p.Path(x[:0], times)
+		for j, v := range x {
+			cols[j] = append(cols[j], math.Log(v/100))
+		}
+	}
+	// log(S/S0) is normal with mean (Mu - Sigma²/2)t and variance Sigma²t.
+	for j, u := range times {
+		testMeanVar(t, "GBM", cols[j], 0.03*u, 0.04*u)
+	}
+}
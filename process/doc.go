@@ -0,0 +1,39 @@
+/*
+Package process simulates sample paths of stochastic processes driven by crazy
+Sources.
+
+Continuous processes, namely Wiener processes, Ornstein-Uhlenbeck processes,
+and geometric Brownian motion, are sampled exactly at caller-specified times
+with Path, which appends the values to a caller-provided slice. Wiener paths
+can also be built by Brownian bridge construction, which concentrates the
+variance of the path in its first few normal variates and so pairs well with
+quasi-Monte Carlo. Point processes produce their arrival times with Arrivals.
+Discrete-time and continuous-time Markov chains on finite state spaces step
+through their states with Step or Path.
+
+To make each path reproducible independently of the others, give path i its
+own Source, the i'th of the non-overlapping streams produced by crazy.Streams:
+
+	for _, s := range crazy.Streams(src, n) {
+		w := process.Wiener{Source: s, Sigma: 1}
+		x = w.Path(x[:0], times)
+		...
+	}
+
+PathSource recreates the stream of a single path, e.g. to replay path i alone.
+*/
+package process
+
+import "github.com/zephyrtronium/crazy"
+
+// PathSource returns a copy of src advanced by i jumps, which is the same
+// stream as crazy.Streams(src, i+1)[i]. src itself is not modified. It takes
+// i jumps, so use crazy.Streams instead to get the streams of many paths. It
+// panics if src is not a crazy.Copier.
+func PathSource(src crazy.Jumper, i int) crazy.Jumper {
+	s := src.(crazy.Copier).Copy().(crazy.Jumper)
+	for ; i > 0; i-- {
+		s.Jump()
+	}
+	return s
+}
@@ -0,0 +1,145 @@
+package process
+
+import (
+	"math"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+// MarkovChain is a discrete-time Markov chain on the states 0, ..., n-1. Each
+// step selects the next state in constant time using an alias table for the
+// row of the transition matrix.
+type MarkovChain struct {
+	// State is the current state.
+	State int
+
+	rows []crazy.Alias
+}
+
+// NewMarkovChain creates a Markov chain drawing from the specified source
+// with the given transition matrix, starting in state. p[i][j] is the
+// probability of moving from state i to state j; rows need not be normalized.
+// It panics if p is not square, if any row has no positive entries, or if
+// state is out of range.
+func NewMarkovChain(src crazy.Source, p [][]float64, state int) *MarkovChain {
+	n := len(p)
+	if state < 0 || state >= n {
+		panic("process: initial state out of range")
+	}
+	m := &MarkovChain{State: state, rows: make([]crazy.Alias, n)}
+	for i, r := range p {
+		if len(r) != n {
+			panic("process: transition matrix is not square")
+		}
+		m.rows[i] = crazy.NewAlias(src, r)
+	}
+	return m
+}
+
+// Step advances the chain by one step and returns the new state.
+func (m *MarkovChain) Step() int {
+	m.State = m.rows[m.State].Int()
+	return m.State
+}
+
+// Path advances the chain by n steps, appending each new state to dst, and
+// returns the result.
+func (m *MarkovChain) Path(dst []int, n int) []int {
+	for i := 0; i < n; i++ {
+		dst = append(dst, m.Step())
+	}
+	return dst
+}
+
+// CTMC is a continuous-time Markov chain on the states 0, ..., n-1, simulated
+// by the direct method of Gillespie, "Exact stochastic simulation of coupled
+// chemical reactions," J. Phys. Chem. 81(25), 1977. The chain holds in each
+// state for an exponentially distributed time with rate equal to the total
+// rate out of the state, then jumps to another state chosen with probability
+// proportional to the rate of each transition.
+type CTMC struct {
+	crazy.Source
+	// State is the current state.
+	State int
+	// Time is the time of the most recent jump, or the starting time.
+	Time float64
+
+	exit []float64
+	jump []crazy.Alias
+}
+
+// NewCTMC creates a continuous-time Markov chain drawing from the specified
+// source with the given rate matrix, starting in state at time zero. q[i][j]
+// for i != j is the rate of transitions from state i to state j; the diagonal
+// is ignored. States with no outgoing transitions are absorbing. It panics if
+// q is not square, if any off-diagonal rate is negative or not finite, or if
+// state is out of range.
+func NewCTMC(src crazy.Source, q [][]float64, state int) *CTMC {
+	n := len(q)
+	if state < 0 || state >= n {
+		panic("process: initial state out of range")
+	}
+	c := &CTMC{
+		Source: src,
+		State:  state,
+		exit:   make([]float64, n),
+		jump:   make([]crazy.Alias, n),
+	}
+	w := make([]float64, n)
+	for i, r := range q {
+		if len(r) != n {
+			panic("process: rate matrix is not square")
+		}
+		copy(w, r)
+		w[i] = 0
+		for _, v := range w {
+			if !(v >= 0) || math.IsInf(v, 1) {
+				panic("process: invalid transition rate")
+			}
+			c.exit[i] += v
+		}
+		if c.exit[i] > 0 {
+			c.jump[i] = crazy.NewAlias(src, w)
+		}
+	}
+	return c
+}
+
+// Step advances the chain to its next jump and returns the time of the jump
+// and the new state. If the current state is absorbing, the time is +Inf and
+// the state does not change.
+func (c *CTMC) Step() (t float64, state int) {
+	r := c.exit[c.State]
+	if r == 0 {
+		c.Time = math.Inf(1)
+		return c.Time, c.State
+	}
+	c.Time += crazy.NewExponential(c.Source, r).Next()
+	c.State = c.jump[c.State].Int()
+	return c.Time, c.State
+}
+
+// Path advances the chain through all jumps before end, appending the time of
+// each jump to times and the state entered to states, and returns the
+// results. The chain is left in the state it occupies at end, with Time set to
+// end, so that consecutive calls simulate consecutive intervals.
+func (c *CTMC) Path(times []float64, states []int, end float64) ([]float64, []int) {
+	for {
+		r := c.exit[c.State]
+		if r == 0 {
+			break
+		}
+		t := c.Time + crazy.NewExponential(c.Source, r).Next()
+		if t >= end {
+			// The exponential holding time is memoryless, so discarding
+			// the overshoot does not bias the next interval.
+			break
+		}
+		c.Time = t
+		c.State = c.jump[c.State].Int()
+		times = append(times, t)
+		states = append(states, c.State)
+	}
+	c.Time = end
+	return times, states
+}
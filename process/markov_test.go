@@ -0,0 +1,102 @@
+package process
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+func TestMarkovChain(t *testing.T) {
+	p := [][]float64{
+		{0.5, 0.5, 0},
+		{0.25, 0.5, 0.25},
+		{0, 1, 3},
+	}
+	m := NewMarkovChain(crazy.CryptoSeeded(crazy.NewXoshiro(), 32), p, 0)
+	const n = 100000
+	s := m.Path(nil, n)
+	if len(s) != n {
+		t.Fatalf("path has %d steps, want %d", len(s), n)
+	}
+	counts := make([]float64, 3)
+	prev := 0
+	for _, v := range s {
+		if p[prev][v] == 0 {
+			t.Fatalf("impossible transition %d -> %d", prev, v)
+		}
+		counts[v]++
+		prev = v
+	}
+	// The last row normalizes to (0, 1/4, 3/4), so the stationary
+	// distribution is proportional to (1, 2, 2).
+	want := []float64{0.2, 0.4, 0.4}
+	for i, w := range want {
+		// Allow for autocorrelation in the path.
+		if got := counts[i] / n; math.Abs(got-w) > 0.015 {
+			t.Errorf("state %d has frequency %v, want %v", i, got, w)
+		}
+	}
+	if m.State != s[n-1] {
+		t.Errorf("chain is in state %d, path ended in %d", m.State, s[n-1])
+	}
+}
+
+func TestCTMC(t *testing.T) {
+	// Two states with rates 2 out of state 0 and 3 out of state 1 spend 3/5
+	// of the time in state 0.
+	q := [][]float64{
+		{-2, 2},
+		{3, -3},
+	}
+	c := NewCTMC(crazy.CryptoSeeded(crazy.NewXoshiro(), 32), q, 0)
+	var times []float64
+	var states []int
+	// Simulate in pieces to check that intervals join correctly.
+	for end := 100.0; end <= 10000; end += 100 {
+		times, states = c.Path(times, states, end)
+		if c.Time != end {
+			t.Fatalf("chain time is %v after path to %v", c.Time, end)
+		}
+	}
+	var in0 float64
+	prev, state := 0.0, 0
+	for i, u := range times {
+		if u < prev {
+			t.Fatalf("jump times decrease: %v then %v", prev, u)
+		}
+		if states[i] == state {
+			t.Fatalf("jump %d does not change state", i)
+		}
+		if state == 0 {
+			in0 += u - prev
+		}
+		prev, state = u, states[i]
+	}
+	if state == 0 {
+		in0 += 10000 - prev
+	}
+	if got := in0 / 10000; math.Abs(got-0.6) > 0.02 {
+		t.Errorf("fraction of time in state 0 is %v, want 0.6", got)
+	}
+	// Expected number of jumps is 2 * 10000 / (1/2 + 1/3) = 24000.
+	if n := float64(len(times)); math.Abs(n-24000) > 5*math.Sqrt(24000) {
+		t.Errorf("%v jumps, want about 24000", n)
+	}
+}
+
+func TestCTMCAbsorbing(t *testing.T) {
+	q := [][]float64{
+		{0, 1, 1},
+		{0, 0, 0},
+		{0, 0, 0},
+	}
+	c := NewCTMC(crazy.CryptoSeeded(crazy.NewXoshiro(), 32), q, 0)
+	_, s := c.Step()
+	if s != 1 && s != 2 {
+		t.Fatalf("jumped to state %d", s)
+	}
+	if u, s2 := c.Step(); !math.IsInf(u, 1) || s2 != s {
+		t.Errorf("absorbing state stepped to %d at %v", s2, u)
+	}
+}
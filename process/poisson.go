@@ -0,0 +1,51 @@
+package process
+
+import "github.com/zephyrtronium/crazy"
+
+// Poisson is a homogeneous Poisson process with constant rate.
+type Poisson struct {
+	crazy.Source
+	// Rate is the expected number of arrivals per unit time.
+	Rate float64
+}
+
+// Arrivals appends the arrival times of the process in [0, end) to dst in
+// increasing order and returns the result.
+func (p Poisson) Arrivals(dst []float64, end float64) []float64 {
+	e := crazy.NewExponential(p.Source, p.Rate)
+	for t := e.Next(); t < end; t += e.Next() {
+		dst = append(dst, t)
+	}
+	return dst
+}
+
+// Inhomogeneous is an inhomogeneous Poisson process with time-varying rate,
+// simulated by thinning a homogeneous process with rate Max. See Lewis and
+// Shedler, "Simulation of nonhomogeneous Poisson processes by thinning,"
+// Naval Research Logistics Quarterly 26(3), 1979.
+type Inhomogeneous struct {
+	crazy.Source
+	// Rate gives the arrival rate at time t.
+	Rate func(t float64) float64
+	// Max bounds Rate over the simulated interval. The closer it is to the
+	// true maximum, the fewer candidate arrivals are rejected.
+	Max float64
+}
+
+// Arrivals appends the arrival times of the process in [0, end) to dst in
+// increasing order and returns the result. It panics if Rate exceeds Max at
+// any candidate arrival time.
+func (p Inhomogeneous) Arrivals(dst []float64, end float64) []float64 {
+	e := crazy.NewExponential(p.Source, p.Max)
+	u := crazy.Uniform0_1{Source: p.Source}
+	for t := e.Next(); t < end; t += e.Next() {
+		r := p.Rate(t)
+		if r > p.Max {
+			panic("process: rate exceeds bound")
+		}
+		if u.Next()*p.Max < r {
+			dst = append(dst, t)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,67 @@
+package process
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+func TestPoisson(t *testing.T) {
+	p := Poisson{Source: crazy.CryptoSeeded(crazy.NewXoshiro(), 32), Rate: 3}
+	const n = 10000
+	var counts []float64
+	var a []float64
+	for i := 0; i < n; i++ {
+		a = p.Arrivals(a[:0], 2)
+		for j, v := range a {
+			if v < 0 || v >= 2 || j > 0 && v < a[j-1] {
+				t.Fatalf("bad arrivals %v", a)
+			}
+		}
+		counts = append(counts, float64(len(a)))
+	}
+	testMeanVar(t, "count", counts, 6, 6)
+}
+
+func TestInhomogeneous(t *testing.T) {
+	p := Inhomogeneous{
+		Source: crazy.CryptoSeeded(crazy.NewXoshiro(), 32),
+		Rate:   func(t float64) float64 { return 2 + math.Sin(t) },
+		Max:    3,
+	}
+	const n = 10000
+	var counts, early []float64
+	var a []float64
+	for i := 0; i < n; i++ {
+		a = p.Arrivals(a[:0], 4)
+		counts = append(counts, float64(len(a)))
+		k := 0
+		for _, v := range a {
+			if v < 1 {
+				k++
+			}
+		}
+		early = append(early, float64(k))
+	}
+	// The count over [0, T) is Poisson with mean equal to the integrated
+	// rate, 2T + 1 - cos(T).
+	m := 8 + 1 - math.Cos(4)
+	testMeanVar(t, "count", counts, m, m)
+	m = 2 + 1 - math.Cos(1)
+	testMeanVar(t, "count in [0, 1)", early, m, m)
+}
+
+func TestInhomogeneousBound(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("no panic with rate exceeding bound")
+		}
+	}()
+	p := Inhomogeneous{
+		Source: crazy.CryptoSeeded(crazy.NewXoshiro(), 32),
+		Rate:   func(t float64) float64 { return 5 },
+		Max:    3,
+	}
+	p.Arrivals(nil, 10)
+}
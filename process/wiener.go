@@ -0,0 +1,105 @@
+package process
+
+import (
+	"math"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+// Wiener is a Wiener process with drift, X(t) = X0 + Mu*t + Sigma*W(t), where
+// W is standard Brownian motion.
+type Wiener struct {
+	crazy.Source
+	// X0 is the value at time zero.
+	X0 float64
+	// Mu is the drift.
+	Mu float64
+	// Sigma is the volatility.
+	Sigma float64
+}
+
+// Path appends the values of the process at each of the times in t to dst and
+// returns the result. t must be nonnegative and nondecreasing.
+func (w Wiener) Path(dst, t []float64) []float64 {
+	z := crazy.NewNormal(w.Source, 0, 1)
+	x, s := w.X0, 0.0
+	for _, u := range t {
+		dt := u - s
+		x += w.Mu*dt + w.Sigma*math.Sqrt(dt)*z.Next()
+		s = u
+		dst = append(dst, x)
+	}
+	return dst
+}
+
+// Bridge appends the values of the process at each of the times in t to dst
+// using Brownian bridge construction and returns the result. The value at the
+// last time is drawn first, then the values at the midpoints of successively
+// smaller intervals conditioned on their endpoints. t must be nonnegative and
+// nondecreasing.
+func (w Wiener) Bridge(dst, t []float64) []float64 {
+	z := crazy.NewNormal(w.Source, 0, 1)
+	k := len(dst)
+	for range t {
+		dst = append(dst, z.Next())
+	}
+	w.bridge(dst[k:], t, dst[k:])
+	return dst
+}
+
+// BridgeNormals is like Bridge, but instead of drawing from the source, it
+// uses the given standard normal variates, which must be at least as many as
+// the times in t. The first variate determines the value at the last time,
+// and later ones fill in progressively finer detail, so z may be taken from
+// the leading coordinates of a quasi-random sequence transformed to normal.
+func (w Wiener) BridgeNormals(dst, t, z []float64) []float64 {
+	k := len(dst)
+	for range t {
+		dst = append(dst, 0)
+	}
+	w.bridge(dst[k:], t, z[:len(t)])
+	return dst
+}
+
+// bridge fills x from the normals in z, which may alias x.
+func (w Wiener) bridge(x, t, z []float64) {
+	n := len(t)
+	if n == 0 {
+		return
+	}
+	// Order the construction breadth-first so that the i'th normal is used
+	// for the i'th point generated.
+	type span struct{ l, r int }
+	queue := []span{{-1, n - 1}}
+	at := func(i int) (float64, float64) {
+		if i < 0 {
+			return 0, w.X0
+		}
+		return t[i], x[i]
+	}
+	zs := append([]float64(nil), z...)
+	tr := t[n-1]
+	x[n-1] = w.X0 + w.Mu*tr + w.Sigma*math.Sqrt(tr)*zs[0]
+	for k := 1; len(queue) > 0; {
+		s := queue[0]
+		queue = queue[1:]
+		m := (s.l + s.r + 1) / 2
+		if m <= s.l || m >= s.r {
+			continue
+		}
+		tl, xl := at(s.l)
+		tr, xr := at(s.r)
+		tm := t[m]
+		var mean, sd float64
+		if tr > tl {
+			f := (tm - tl) / (tr - tl)
+			mean = xl + f*(xr-xl)
+			sd = w.Sigma * math.Sqrt((tm-tl)*(tr-tm)/(tr-tl))
+		} else {
+			mean = xl
+		}
+		x[m] = mean + sd*zs[k]
+		k++
+		queue = append(queue, span{s.l, m}, span{m, s.r})
+	}
+}
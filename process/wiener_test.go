@@ -0,0 +1,101 @@
+package process
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+// testMeanVar checks the sample mean and variance of xs against the expected
+// values, with tolerance based on the standard errors for a normal population.
+func testMeanVar(t *testing.T, name string, xs []float64, mean, variance float64) {
+	t.Helper()
+	n := float64(len(xs))
+	var m, v float64
+	for _, x := range xs {
+		m += x / n
+	}
+	for _, x := range xs {
+		v += (x - m) * (x - m) / (n - 1)
+	}
+	if math.Abs(m-mean) > 5*math.Sqrt(variance/n) {
+		t.Errorf("%s mean is %v, want %v", name, m, mean)
+	}
+	if math.Abs(v-variance) > 5*variance*math.Sqrt(2/n) {
+		t.Errorf("%s variance is %v, want %v", name, v, variance)
+	}
+}
+
+func TestWienerPath(t *testing.T) {
+	times := []float64{0.5, 1, 1, 3}
+	for _, c := range []struct {
+		name string
+		path func(w Wiener, dst, t []float64) []float64
+	}{
+		{"Path", Wiener.Path},
+		{"Bridge", Wiener.Bridge},
+	} {
+		w := Wiener{Source: crazy.CryptoSeeded(crazy.NewXoshiro(), 32), X0: 1, Mu: 0.5, Sigma: 2}
+		const n = 10000
+		cols := make([][]float64, len(times))
+		var incr []float64
+		var x []float64
+		for i := 0; i < n; i++ {
+			x = c.path(w, x[:0], times)
+			if x[1] != x[2] {
+				t.Fatalf("%s: values at equal times differ: %v", c.name, x)
+			}
+			for j, v := range x {
+				cols[j] = append(cols[j], v)
+			}
+			incr = append(incr, x[3]-x[1])
+		}
+		for j, u := range times {
+			testMeanVar(t, c.name, cols[j], 1+0.5*u, 4*u)
+		}
+		// Increments are independent of the past and have variance
+		// proportional to elapsed time.
+		testMeanVar(t, c.name+" increment", incr, 1, 8)
+	}
+}
+
+func TestWienerBridgeNormals(t *testing.T) {
+	w := Wiener{X0: 2, Mu: 1, Sigma: 3}
+	times := []float64{0.25, 0.5, 0.75, 1, 1.5}
+	z := make([]float64, len(times))
+	x := w.BridgeNormals(nil, times, z)
+	for i, u := range times {
+		if math.Abs(x[i]-(2+u)) > 1e-14 {
+			t.Errorf("zero normals give %v at %v, want %v", x[i], u, 2+u)
+		}
+	}
+	// The first normal alone moves the endpoint and scales the whole path
+	// linearly.
+	z[0] = 1
+	x = w.BridgeNormals(x[:0], times, z)
+	for i, u := range times {
+		want := 2 + u + 3*math.Sqrt(1.5)*u/1.5
+		if math.Abs(x[i]-want) > 1e-14 {
+			t.Errorf("first normal gives %v at %v, want %v", x[i], u, want)
+		}
+	}
+}
+
+func TestPathSource(t *testing.T) {
+	src := crazy.NewXoshiro()
+	src.SeedIV([]byte("process"))
+	s := PathSource(src, 3)
+	want := crazy.Streams(src.Copy().(crazy.Jumper), 4)[3]
+	a, b := crazy.RNG{Source: s}, crazy.RNG{Source: want}
+	for i := 0; i < 10; i++ {
+		if x, y := a.Uint64(), b.Uint64(); x != y {
+			t.Fatalf("value %d is %x, want %x", i, x, y)
+		}
+	}
+	c := crazy.NewXoshiro()
+	c.SeedIV([]byte("process"))
+	if (crazy.RNG{Source: src}).Uint64() != (crazy.RNG{Source: c}).Uint64() {
+		t.Error("PathSource modified its argument")
+	}
+}
@@ -0,0 +1,33 @@
+/*
+Package qmc provides low-discrepancy quasi-random sequences for quasi-Monte
+Carlo integration.
+
+Sobol, Halton, and Kronecker sequences produce points in the unit hypercube
+[0, 1)^d that fill space more evenly than pseudo-random points. Each can be
+randomized using a crazy Source, either by scrambling or by a random shift,
+which makes every point uniformly distributed while preserving the
+low-discrepancy structure, so that independent randomizations give unbiased
+estimates with computable error.
+
+The sequences implement crazy.VectorDistribution, so they can be used anywhere
+random vectors are.
+
+For designs of a fixed size, LatinHypercube, MaximinLatinHypercube, and
+Jittered produce stratified samples as matrices with one point per row.
+ImanConover induces a rank correlation among the columns of such a sample, and
+Transform maps it through the quantile functions of crazy.Analytic
+distributions.
+*/
+package qmc
+
+import "github.com/zephyrtronium/crazy"
+
+// A Sequence produces successive points of a low-discrepancy sequence.
+type Sequence interface {
+	crazy.VectorDistribution
+	// Dim returns the dimension of the points in the sequence.
+	Dim() int
+	// Seek moves the sequence such that the next point is the one at index
+	// i, with zero being the first.
+	Seek(i uint64)
+}
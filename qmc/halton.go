@@ -0,0 +1,134 @@
+package qmc
+
+import (
+	"math"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+// Halton generates a Halton sequence, whose jth coordinate is the radical
+// inverse of the point index in the jth prime base. It can optionally be
+// randomized with random digit permutations or a random shift.
+type Halton struct {
+	base []uint64
+	// ndig[j] is the number of digits needed in base[j] for full float64
+	// precision.
+	ndig []int
+	// Digit d at position k in dimension j is scrambled to
+	// (mul[j][k]*d + add[j][k]) mod base[j]. mul is nil if unscrambled.
+	mul, add [][]uint64
+	shift    []float64
+	n        uint64
+}
+
+// NewHalton creates a Halton sequence in dims dimensions. It panics if dims
+// is less than 1.
+func NewHalton(dims int) *Halton {
+	if dims < 1 {
+		panic("qmc: Halton dimension out of range")
+	}
+	h := &Halton{
+		base: primes(dims),
+		ndig: make([]int, dims),
+	}
+	for j, b := range h.base {
+		h.ndig[j] = int(math.Ceil(53 / math.Log2(float64(b))))
+	}
+	return h
+}
+
+// primes returns the first n primes.
+func primes(n int) []uint64 {
+	p := make([]uint64, 0, n)
+	for c := uint64(2); len(p) < n; c++ {
+		prime := true
+		for _, q := range p {
+			if q*q > c {
+				break
+			}
+			if c%q == 0 {
+				prime = false
+				break
+			}
+		}
+		if prime {
+			p = append(p, c)
+		}
+	}
+	return p
+}
+
+// Dim returns the dimension of the sequence.
+func (h *Halton) Dim() int {
+	return len(h.base)
+}
+
+// Scramble randomizes the sequence by applying an independent random affine
+// permutation d -> (a*d + c) mod b, with a and c drawn from src, to the
+// digits at each position in each dimension. This is the random linear
+// scrambling of Matoušek, "On the L2-discrepancy for anchored boxes," J.
+// Complexity 14, 1998. Unlike the unscrambled sequence, the scrambled
+// sequence has little correlation between dimensions with large bases.
+func (h *Halton) Scramble(src crazy.Source) {
+	r := crazy.RNG{Source: src}
+	h.mul = make([][]uint64, len(h.base))
+	h.add = make([][]uint64, len(h.base))
+	for j, b := range h.base {
+		h.mul[j] = make([]uint64, h.ndig[j])
+		h.add[j] = make([]uint64, h.ndig[j])
+		for k := range h.mul[j] {
+			h.mul[j][k] = 1 + uint64(r.Uintn(uint(b-1)))
+			h.add[j][k] = uint64(r.Uintn(uint(b)))
+		}
+	}
+}
+
+// Shift randomizes the sequence with a Cranley-Patterson rotation, i.e. a
+// random vector drawn from src is added to each point modulo 1.
+func (h *Halton) Shift(src crazy.Source) {
+	u := crazy.Uniform0_1{Source: src}
+	h.shift = make([]float64, len(h.base))
+	for j := range h.shift {
+		h.shift[j] = u.Next()
+	}
+}
+
+// Seek moves the sequence such that the next point is the one at index i.
+func (h *Halton) Seek(i uint64) {
+	h.n = i
+}
+
+// NextVector fills dst, which must have length Dim(), with the next point in
+// the sequence.
+func (h *Halton) NextVector(dst []float64) {
+	for j, b := range h.base {
+		var x float64
+		f := 1 / float64(b)
+		w := f
+		n := h.n
+		if h.mul != nil {
+			// Every digit position is permuted, including the infinitely
+			// many leading zeros of n, up to the limit of precision.
+			mul, add := h.mul[j], h.add[j]
+			for k := 0; k < h.ndig[j]; k++ {
+				x += float64((mul[k]*(n%b)+add[k])%b) * w
+				n /= b
+				w *= f
+			}
+		} else {
+			for ; n != 0; n /= b {
+				x += float64(n%b) * w
+				w *= f
+			}
+		}
+		if h.shift != nil {
+			x += h.shift[j]
+			x -= math.Floor(x)
+		}
+		if x >= 1 {
+			x = math.Nextafter(1, 0)
+		}
+		dst[j] = x
+	}
+	h.n++
+}
@@ -0,0 +1,70 @@
+package qmc
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+func TestHaltonPoints(t *testing.T) {
+	want := [][]float64{
+		{0, 0},
+		{1.0 / 2, 1.0 / 3},
+		{1.0 / 4, 2.0 / 3},
+		{3.0 / 4, 1.0 / 9},
+		{1.0 / 8, 4.0 / 9},
+	}
+	h := NewHalton(2)
+	x := make([]float64, 2)
+	for i, w := range want {
+		h.NextVector(x)
+		for j := range w {
+			if math.Abs(x[j]-w[j]) > 1e-15 {
+				t.Fatalf("point %d is %v, want %v", i, x, w)
+			}
+		}
+	}
+}
+
+func TestHaltonScrambled(t *testing.T) {
+	h := NewHalton(3)
+	h.Scramble(crazy.CryptoSeeded(crazy.NewXoshiro(), 32))
+	// Each base stratifies in its own powers, so check them separately.
+	x := make([]float64, 3)
+	for j, b := range []int{2, 3, 5} {
+		n := int(math.Pow(float64(b), 4))
+		seen := make([]bool, n)
+		h.Seek(0)
+		for i := 0; i < n; i++ {
+			h.NextVector(x)
+			c := int(x[j] * float64(n))
+			if x[j] < 0 || x[j] >= 1 || seen[c] {
+				t.Fatalf("base %d point %d has coordinate %g", b, i, x[j])
+			}
+			seen[c] = true
+		}
+	}
+}
+
+func TestHaltonShift(t *testing.T) {
+	h := NewHalton(50)
+	h.Shift(crazy.CryptoSeeded(crazy.NewXoshiro(), 32))
+	x := make([]float64, 50)
+	for i := 0; i < 1<<12; i++ {
+		h.NextVector(x)
+		for j, v := range x {
+			if v < 0 || v >= 1 {
+				t.Fatalf("point %d coordinate %d is %g", i, j, v)
+			}
+		}
+	}
+}
+
+func BenchmarkHalton(b *testing.B) {
+	h := NewHalton(16)
+	x := make([]float64, 16)
+	for i := 0; i < b.N; i++ {
+		h.NextVector(x)
+	}
+}
@@ -0,0 +1,93 @@
+package qmc
+
+import (
+	"math"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+// Kronecker generates a Kronecker (rank-1 lattice) sequence, whose points are
+// frac(s + n*alpha) for a vector alpha of irrationals and starting point s.
+// Points are computed in 64-bit fixed point, so the sequence is exact for all
+// 2**64 indices.
+type Kronecker struct {
+	alpha, start []uint64
+	n            uint64
+}
+
+// NewKronecker creates a Kronecker sequence with the given generating vector,
+// each element of which should be an irrational number in (0, 1), starting
+// at the origin.
+func NewKronecker(alpha []float64) *Kronecker {
+	k := &Kronecker{
+		alpha: make([]uint64, len(alpha)),
+		start: make([]uint64, len(alpha)),
+	}
+	for j, a := range alpha {
+		k.alpha[j] = toFixed(a)
+	}
+	return k
+}
+
+// NewR2 creates the R_d sequence of Roberts, "The unreasonable effectiveness
+// of quasirandom sequences," 2018, in dims dimensions. Its generating vector
+// consists of the powers 1/phi**k, k = 1, ..., dims, where phi is the unique
+// positive root of x**(dims+1) = x + 1, and it starts at 1/2 in each
+// coordinate. With dims = 2, this is the R2 sequence. It panics if dims is
+// less than 1.
+func NewR2(dims int) *Kronecker {
+	if dims < 1 {
+		panic("qmc: Kronecker dimension out of range")
+	}
+	// Newton's method for x**(d+1) - x - 1 = 0 from above.
+	phi := 2.0
+	for i := 0; i < 64; i++ {
+		f := math.Pow(phi, float64(dims+1)) - phi - 1
+		df := float64(dims+1)*math.Pow(phi, float64(dims)) - 1
+		phi -= f / df
+	}
+	alpha := make([]float64, dims)
+	for j := range alpha {
+		alpha[j] = math.Pow(1/phi, float64(j+1))
+	}
+	k := NewKronecker(alpha)
+	for j := range k.start {
+		k.start[j] = 1 << 63
+	}
+	return k
+}
+
+// toFixed converts x in [0, 1) to 64-bit fixed point.
+func toFixed(x float64) uint64 {
+	x -= math.Floor(x)
+	return uint64(math.Ldexp(x, 64))
+}
+
+// Dim returns the dimension of the sequence.
+func (k *Kronecker) Dim() int {
+	return len(k.alpha)
+}
+
+// Shift randomizes the sequence with a Cranley-Patterson rotation, replacing
+// the starting point with a random point drawn from src.
+func (k *Kronecker) Shift(src crazy.Source) {
+	r := crazy.RNG{Source: src}
+	for j := range k.start {
+		k.start[j] = r.Uint64()
+	}
+}
+
+// Seek moves the sequence such that the next point is the one at index i.
+func (k *Kronecker) Seek(i uint64) {
+	k.n = i
+}
+
+// NextVector fills dst, which must have length Dim(), with the next point in
+// the sequence.
+func (k *Kronecker) NextVector(dst []float64) {
+	for j, a := range k.alpha {
+		x := k.start[j] + k.n*a
+		dst[j] = float64(x>>11) * (1.0 / (1 << 53))
+	}
+	k.n++
+}
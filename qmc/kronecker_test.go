@@ -0,0 +1,58 @@
+package qmc
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+func TestR2(t *testing.T) {
+	k := NewR2(2)
+	// The plastic number is the generator for R2.
+	const plastic = 1.32471795724474602596
+	x := make([]float64, 2)
+	for i := 0; i < 100; i++ {
+		k.NextVector(x)
+		for j, v := range x {
+			w := 0.5 + float64(i)*math.Pow(plastic, -float64(j+1))
+			w -= math.Floor(w)
+			if math.Abs(v-w) > 1e-12 {
+				t.Fatalf("point %d is %v, coordinate %d want %g", i, x, j, w)
+			}
+		}
+	}
+}
+
+func TestR2Golden(t *testing.T) {
+	// In one dimension, R1 uses the golden ratio.
+	k := NewR2(1)
+	x := make([]float64, 1)
+	k.Seek(1)
+	k.NextVector(x)
+	if w := 0.5 + (math.Sqrt(5)-1)/2 - 1; math.Abs(x[0]-w) > 1e-15 {
+		t.Errorf("second point %g, want %g", x[0], w)
+	}
+}
+
+func TestKroneckerShift(t *testing.T) {
+	k := NewR2(8)
+	k.Shift(crazy.CryptoSeeded(crazy.NewXoshiro(), 32))
+	x := make([]float64, 8)
+	m := make([]float64, 8)
+	n := 1 << 12
+	for i := 0; i < n; i++ {
+		k.NextVector(x)
+		for j, v := range x {
+			if v < 0 || v >= 1 {
+				t.Fatalf("point %d coordinate %d is %g", i, j, v)
+			}
+			m[j] += v
+		}
+	}
+	for j, v := range m {
+		if v /= float64(n); math.Abs(v-0.5) > 0.01 {
+			t.Errorf("coordinate %d has mean %g", j, v)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package qmc
+
+import (
+	"math"
+	"sort"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+// LatinHypercube returns a Latin hypercube sample of n points in [0, 1)^dims.
+// Each coordinate of the sample has exactly one point in each of the n
+// intervals [i/n, (i+1)/n), with the point placed uniformly within its
+// interval, and the intervals are paired randomly across coordinates.
+func LatinHypercube(n, dims int, rng crazy.RNG) [][]float64 {
+	x := matrix(n, dims)
+	u := crazy.Uniform0_1{Source: rng.Source}
+	p := make([]int, n)
+	for j := 0; j < dims; j++ {
+		permute(p, rng)
+		for i, s := range p {
+			x[i][j] = (float64(s) + u.Next()) / float64(n)
+		}
+	}
+	return x
+}
+
+// MaximinLatinHypercube returns a Latin hypercube sample of n points in
+// [0, 1)^dims which is improved by iters random exchanges of coordinates
+// between points, keeping each exchange that does not decrease the minimum
+// distance between any two points.
+func MaximinLatinHypercube(n, dims, iters int, rng crazy.RNG) [][]float64 {
+	x := LatinHypercube(n, dims, rng)
+	if n < 3 || dims == 0 {
+		// There is nothing to exchange.
+		return x
+	}
+	// d[i][j] is the squared distance between points i and j.
+	d := matrix(n, n)
+	for i := range x {
+		for j := 0; j < i; j++ {
+			d[i][j] = dist2(x[i], x[j])
+			d[j][i] = d[i][j]
+		}
+	}
+	best := minDist(d)
+	ri, rj := make([]float64, n), make([]float64, n)
+	for it := 0; it < iters; it++ {
+		c := rng.Intn(dims)
+		a := rng.Intn(n)
+		b := rng.Intn(n - 1)
+		if b >= a {
+			b++
+		}
+		copy(ri, d[a])
+		copy(rj, d[b])
+		x[a][c], x[b][c] = x[b][c], x[a][c]
+		update(d, x, a)
+		update(d, x, b)
+		if m := minDist(d); m >= best {
+			best = m
+			continue
+		}
+		// Revert.
+		x[a][c], x[b][c] = x[b][c], x[a][c]
+		copy(d[a], ri)
+		copy(d[b], rj)
+		for k := range d {
+			d[k][a], d[k][b] = ri[k], rj[k]
+		}
+	}
+	return x
+}
+
+// Jittered returns a stratified sample of k**dims points in [0, 1)^dims. The
+// unit hypercube is divided into a grid of k**dims congruent cells, and one
+// point is placed uniformly within each cell.
+func Jittered(k, dims int, rng crazy.RNG) [][]float64 {
+	n := 1
+	for j := 0; j < dims; j++ {
+		n *= k
+	}
+	x := matrix(n, dims)
+	u := crazy.Uniform0_1{Source: rng.Source}
+	for i := range x {
+		c := i
+		for j := range x[i] {
+			x[i][j] = (float64(c%k) + u.Next()) / float64(k)
+			c /= k
+		}
+	}
+	return x
+}
+
+// ImanConover reorders the values within each column of the sample x so that
+// the rank correlation between columns approximates the target correlation
+// matrix corr, using the method of Iman and Conover, "A distribution-free
+// approach to inducing rank correlation among input variables," Comm.
+// Statist. Simul. Comput. 11(3), 1982. The marginal distribution of each
+// column is unchanged. The sample must have more rows than columns, so that
+// the scores used to induce the correlation are not linearly dependent. It
+// panics if x is nonempty and has no more rows than columns or if corr is not
+// positive definite.
+func ImanConover(x, corr [][]float64, rng crazy.RNG) {
+	n := len(x)
+	if n == 0 {
+		return
+	}
+	k := len(x[0])
+	if n <= k {
+		panic("qmc: Iman-Conover sample needs more rows than columns")
+	}
+	// Van der Waerden scores, randomly permuted in each column.
+	z := crazy.Normal{Mu: 0, Sigma: 1}
+	s := matrix(n, k)
+	p := make([]int, n)
+	for j := 0; j < k; j++ {
+		permute(p, rng)
+		for i, v := range p {
+			s[i][j] = z.Quantile(float64(v+1) / float64(n+1))
+		}
+	}
+	// The scores' own correlation E = Q Q^T is replaced by corr = P P^T:
+	// T = S Q^-T P^T.
+	q := cholesky(correlation(s))
+	pc := cholesky(corr)
+	m := mul(pc, lowerInverse(q))
+	t := matrix(n, k)
+	for i := range t {
+		for a := 0; a < k; a++ {
+			var v float64
+			for b := 0; b <= a; b++ {
+				v += m[a][b] * s[i][b]
+			}
+			t[i][a] = v
+		}
+	}
+	// Rearrange x to have the same ranks as t.
+	col := make([]float64, n)
+	idx := make([]int, n)
+	for j := 0; j < k; j++ {
+		for i := range x {
+			col[i] = x[i][j]
+			idx[i] = i
+		}
+		sort.Float64s(col)
+		sort.Slice(idx, func(a, b int) bool { return t[idx[a]][j] < t[idx[b]][j] })
+		for r, i := range idx {
+			x[i][j] = col[r]
+		}
+	}
+}
+
+// Transform maps each column j of the sample x in place through the quantile
+// function of dists[j], turning a sample of [0, 1)^d into a sample of the
+// product of the distributions. It panics if len(dists) is less than the
+// number of columns.
+func Transform(x [][]float64, dists ...crazy.Analytic) {
+	for _, r := range x {
+		for j, v := range r {
+			r[j] = dists[j].Quantile(v)
+		}
+	}
+}
+
+// matrix allocates an n×k matrix.
+func matrix(n, k int) [][]float64 {
+	b := make([]float64, n*k)
+	x := make([][]float64, n)
+	for i := range x {
+		x[i] = b[i*k : (i+1)*k : (i+1)*k]
+	}
+	return x
+}
+
+// permute fills p with a uniformly random permutation of 0, ..., len(p)-1.
+func permute(p []int, rng crazy.RNG) {
+	for i := range p {
+		j := rng.Intn(i + 1)
+		p[i] = p[j]
+		p[j] = i
+	}
+}
+
+func dist2(a, b []float64) float64 {
+	var s float64
+	for i, v := range a {
+		d := v - b[i]
+		s += d * d
+	}
+	return s
+}
+
+// update recomputes distances involving point i.
+func update(d, x [][]float64, i int) {
+	for j := range x {
+		if j != i {
+			d[i][j] = dist2(x[i], x[j])
+			d[j][i] = d[i][j]
+		}
+	}
+}
+
+func minDist(d [][]float64) float64 {
+	m := math.Inf(1)
+	for i := range d {
+		for _, v := range d[i][:i] {
+			if v < m {
+				m = v
+			}
+		}
+	}
+	return m
+}
+
+// correlation computes the correlation matrix of the columns of x.
+func correlation(x [][]float64) [][]float64 {
+	n, k := len(x), len(x[0])
+	mean := make([]float64, k)
+	for _, r := range x {
+		for j, v := range r {
+			mean[j] += v / float64(n)
+		}
+	}
+	c := matrix(k, k)
+	for _, r := range x {
+		for a := 0; a < k; a++ {
+			for b := 0; b <= a; b++ {
+				c[a][b] += (r[a] - mean[a]) * (r[b] - mean[b])
+			}
+		}
+	}
+	for a := 0; a < k; a++ {
+		for b := 0; b < a; b++ {
+			c[a][b] /= math.Sqrt(c[a][a] * c[b][b])
+			c[b][a] = c[a][b]
+		}
+	}
+	for a := 0; a < k; a++ {
+		c[a][a] = 1
+	}
+	return c
+}
+
+// cholesky computes the lower triangular Cholesky factor of a.
+func cholesky(a [][]float64) [][]float64 {
+	n := len(a)
+	l := matrix(n, n)
+	for j := 0; j < n; j++ {
+		s := a[j][j]
+		for k := 0; k < j; k++ {
+			s -= l[j][k] * l[j][k]
+		}
+		if !(s > 0) {
+			panic("qmc: matrix is not positive definite")
+		}
+		l[j][j] = math.Sqrt(s)
+		for i := j + 1; i < n; i++ {
+			s := a[i][j]
+			for k := 0; k < j; k++ {
+				s -= l[i][k] * l[j][k]
+			}
+			l[i][j] = s / l[j][j]
+		}
+	}
+	return l
+}
+
+// lowerInverse inverts the lower triangular matrix l.
+func lowerInverse(l [][]float64) [][]float64 {
+	n := len(l)
+	m := matrix(n, n)
+	for j := 0; j < n; j++ {
+		m[j][j] = 1 / l[j][j]
+		for i := j + 1; i < n; i++ {
+			var s float64
+			for k := j; k < i; k++ {
+				s += l[i][k] * m[k][j]
+			}
+			m[i][j] = -s / l[i][i]
+		}
+	}
+	return m
+}
+
+// mul multiplies square matrices.
+func mul(a, b [][]float64) [][]float64 {
+	n := len(a)
+	c := matrix(n, n)
+	for i := range c {
+		for j := range c[i] {
+			var s float64
+			for k := 0; k < n; k++ {
+				s += a[i][k] * b[k][j]
+			}
+			c[i][j] = s
+		}
+	}
+	return c
+}
@@ -0,0 +1,169 @@
+package qmc
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+func testRNG() crazy.RNG {
+	return crazy.RNG{Source: crazy.CryptoSeeded(crazy.NewXoshiro(), 32)}
+}
+
+func TestLatinHypercube(t *testing.T) {
+	const n, d = 50, 4
+	x := LatinHypercube(n, d, testRNG())
+	if len(x) != n {
+		t.Fatalf("got %d points, want %d", len(x), n)
+	}
+	for j := 0; j < d; j++ {
+		seen := make([]bool, n)
+		for i := range x {
+			v := x[i][j]
+			if v < 0 || v >= 1 {
+				t.Fatalf("point %d coordinate %d is %v", i, j, v)
+			}
+			k := int(v * n)
+			if seen[k] {
+				t.Fatalf("coordinate %d has two points in stratum %d", j, k)
+			}
+			seen[k] = true
+		}
+	}
+}
+
+func TestMaximinLatinHypercubeZeroDims(t *testing.T) {
+	x := MaximinLatinHypercube(5, 0, 100, testRNG())
+	if len(x) != 5 || len(x[0]) != 0 {
+		t.Errorf("got %d points of dimension %d, want 5 of 0", len(x), len(x[0]))
+	}
+}
+
+func TestMaximinLatinHypercube(t *testing.T) {
+	const n, d = 20, 2
+	rng := testRNG()
+	var plain, opt float64
+	for r := 0; r < 20; r++ {
+		plain += minPairDist(LatinHypercube(n, d, rng))
+		x := MaximinLatinHypercube(n, d, 2000, rng)
+		opt += minPairDist(x)
+		// Exchanges must preserve the Latin property.
+		for j := 0; j < d; j++ {
+			seen := make([]bool, n)
+			for i := range x {
+				k := int(x[i][j] * n)
+				if seen[k] {
+					t.Fatalf("coordinate %d has two points in stratum %d", j, k)
+				}
+				seen[k] = true
+			}
+		}
+	}
+	if opt <= plain {
+		t.Errorf("maximin designs have mean min distance %v, plain %v", opt/20, plain/20)
+	}
+}
+
+func minPairDist(x [][]float64) float64 {
+	m := math.Inf(1)
+	for i := range x {
+		for j := 0; j < i; j++ {
+			m = math.Min(m, dist2(x[i], x[j]))
+		}
+	}
+	return math.Sqrt(m)
+}
+
+func TestJittered(t *testing.T) {
+	const k, d = 4, 3
+	x := Jittered(k, d, testRNG())
+	if len(x) != k*k*k {
+		t.Fatalf("got %d points, want %d", len(x), k*k*k)
+	}
+	seen := make(map[[d]int]bool)
+	for _, p := range x {
+		var c [d]int
+		for j, v := range p {
+			if v < 0 || v >= 1 {
+				t.Fatalf("point %v outside unit cube", p)
+			}
+			c[j] = int(v * k)
+		}
+		if seen[c] {
+			t.Fatalf("two points in cell %v", c)
+		}
+		seen[c] = true
+	}
+}
+
+func TestImanConover(t *testing.T) {
+	const n = 2000
+	corr := [][]float64{
+		{1, 0.7, -0.3},
+		{0.7, 1, 0},
+		{-0.3, 0, 1},
+	}
+	rng := testRNG()
+	x := LatinHypercube(n, 3, rng)
+	before := make([][]float64, 3)
+	for j := range before {
+		for i := range x {
+			before[j] = append(before[j], x[i][j])
+		}
+		sort.Float64s(before[j])
+	}
+	ImanConover(x, corr, rng)
+	for j := range before {
+		col := make([]float64, n)
+		for i := range x {
+			col[i] = x[i][j]
+		}
+		sort.Float64s(col)
+		for i := range col {
+			if col[i] != before[j][i] {
+				t.Fatalf("column %d marginal changed", j)
+			}
+		}
+	}
+	// Columns of an LHS are uniform, so Pearson correlation approximates rank
+	// correlation closely.
+	c := correlation(x)
+	for a := range corr {
+		for b := range corr[a] {
+			if math.Abs(c[a][b]-corr[a][b]) > 0.05 {
+				t.Errorf("correlation %d,%d is %v, want %v", a, b, c[a][b], corr[a][b])
+			}
+		}
+	}
+}
+
+func TestImanConoverTooFewRows(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "qmc: Iman-Conover sample needs more rows than columns" {
+			t.Errorf("wrong panic %v", r)
+		}
+	}()
+	corr := [][]float64{{1, 0.5, 0}, {0.5, 1, 0}, {0, 0, 1}}
+	rng := testRNG()
+	ImanConover(LatinHypercube(3, 3, rng), corr, rng)
+}
+
+func TestTransform(t *testing.T) {
+	const n = 1000
+	x := LatinHypercube(n, 2, testRNG())
+	Transform(x, crazy.NewNormal(nil, 3, 2), crazy.Exponential{Rate: 4})
+	var m0, m1 float64
+	for _, p := range x {
+		m0 += p[0] / n
+		m1 += p[1] / n
+	}
+	// LHS means converge much faster than plain Monte Carlo.
+	if math.Abs(m0-3) > 0.01 {
+		t.Errorf("normal mean is %v, want 3", m0)
+	}
+	if math.Abs(m1-0.25) > 0.01 {
+		t.Errorf("exponential mean is %v, want 0.25", m1)
+	}
+}
@@ -0,0 +1,334 @@
+package qmc
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+// DirectionNumbers are the parameters of one dimension of a Sobol sequence:
+// the degree S and coefficients A of a primitive polynomial over GF(2), and
+// the initial direction numbers M, of which there are S.
+type DirectionNumbers struct {
+	S, A uint32
+	M    []uint32
+}
+
+// SobolDims is the maximum dimension of a Sobol sequence using the built-in
+// direction numbers. Dimensions 2 through 21 use the direction numbers of Joe
+// and Kuo. The rest use the remaining primitive polynomials of degree up to
+// 13, in the same order as Joe and Kuo's table, with fixed pseudo-random
+// initial direction numbers. Those still give a valid Sobol sequence with the
+// same t-value, but they lack Joe and Kuo's optimized two-dimensional
+// projections. To use the optimized numbers in every dimension, read the
+// new-joe-kuo-6.21201 file with ReadDirectionNumbers and use NewSobolFrom.
+const SobolDims = 1111
+
+// sobolDirs holds the built-in direction numbers for dimensions 2 through
+// SobolDims.
+var sobolDirs = extendDirections(joeKuo[:], SobolDims-1)
+
+// Sobol generates a Sobol sequence of up to 2**32 points, optionally
+// randomized with Owen scrambling or a random digital shift.
+type Sobol struct {
+	v     [][32]uint32
+	x     []uint32
+	n     uint64
+	seed  []uint32
+	shift []uint32
+}
+
+// NewSobol creates a Sobol sequence in dims dimensions using the built-in
+// direction numbers described at SobolDims. The first 21 dimensions are those
+// of Joe and Kuo, "Constructing Sobol sequences with better two-dimensional
+// projections," SIAM J. Sci. Comput. 30, 2008. It panics if dims is not in
+// [1, SobolDims].
+func NewSobol(dims int) *Sobol {
+	return NewSobolFrom(sobolDirs, dims)
+}
+
+// NewSobolFrom creates a Sobol sequence in dims dimensions using the given
+// direction numbers for the second and subsequent dimensions. The first
+// dimension is always the van der Corput sequence in base 2. It panics if
+// dims is not in [1, len(dirs)+1].
+func NewSobolFrom(dirs []DirectionNumbers, dims int) *Sobol {
+	if dims < 1 || dims > len(dirs)+1 {
+		panic("qmc: Sobol dimension out of range")
+	}
+	s := &Sobol{
+		v: make([][32]uint32, dims),
+		x: make([]uint32, dims),
+	}
+	for k := range s.v[0] {
+		s.v[0][k] = 1 << uint(31-k)
+	}
+	for j := 1; j < dims; j++ {
+		d := dirs[j-1]
+		v := &s.v[j]
+		deg := int(d.S)
+		for k := 0; k < deg && k < 32; k++ {
+			v[k] = d.M[k] << uint(31-k)
+		}
+		for k := deg; k < 32; k++ {
+			w := v[k-deg] ^ v[k-deg]>>uint(deg)
+			for i := 1; i < deg; i++ {
+				if d.A>>uint(deg-1-i)&1 != 0 {
+					w ^= v[k-i]
+				}
+			}
+			v[k] = w
+		}
+	}
+	return s
+}
+
+// ReadDirectionNumbers parses direction numbers in the format distributed by
+// Joe and Kuo, with one dimension per line giving d, s, a, and the m_i. A
+// header line, if present, is skipped. The result can be passed to
+// NewSobolFrom; e.g., the file new-joe-kuo-6.21201 gives Sobol sequences of
+// up to 21201 dimensions.
+func ReadDirectionNumbers(r io.Reader) ([]DirectionNumbers, error) {
+	var dirs []DirectionNumbers
+	sc := bufio.NewScanner(r)
+	line := 0
+	for sc.Scan() {
+		line++
+		f := strings.Fields(sc.Text())
+		if len(f) == 0 {
+			continue
+		}
+		if _, err := strconv.ParseUint(f[0], 10, 32); err != nil {
+			if line == 1 {
+				continue
+			}
+			return dirs, fmt.Errorf("qmc: line %d: %v", line, err)
+		}
+		if len(f) < 3 {
+			return dirs, fmt.Errorf("qmc: line %d: too few fields", line)
+		}
+		var v [3]uint64
+		for i := range v {
+			n, err := strconv.ParseUint(f[i], 10, 32)
+			if err != nil {
+				return dirs, fmt.Errorf("qmc: line %d: %v", line, err)
+			}
+			v[i] = n
+		}
+		d := DirectionNumbers{S: uint32(v[1]), A: uint32(v[2])}
+		if len(f) != 3+int(d.S) {
+			return dirs, fmt.Errorf("qmc: line %d: expected %d direction numbers, have %d", line, d.S, len(f)-3)
+		}
+		for _, s := range f[3:] {
+			n, err := strconv.ParseUint(s, 10, 32)
+			if err != nil {
+				return dirs, fmt.Errorf("qmc: line %d: %v", line, err)
+			}
+			d.M = append(d.M, uint32(n))
+		}
+		dirs = append(dirs, d)
+	}
+	return dirs, sc.Err()
+}
+
+// Dim returns the dimension of the sequence.
+func (s *Sobol) Dim() int {
+	return len(s.v)
+}
+
+// Scramble applies nested uniform (Owen) scrambling to the sequence, with
+// the random permutations seeded from src. This uses the hash-based
+// approximation of Burley, "Practical hash-based Owen scrambling," JCGT 9(4),
+// 2020.
+func (s *Sobol) Scramble(src crazy.Source) {
+	r := crazy.RNG{Source: src}
+	s.seed = make([]uint32, len(s.v))
+	for i := range s.seed {
+		s.seed[i] = r.Uint32()
+	}
+}
+
+// Shift applies a random digital shift to the sequence, i.e. each coordinate
+// is XORed with a random value drawn from src.
+func (s *Sobol) Shift(src crazy.Source) {
+	r := crazy.RNG{Source: src}
+	s.shift = make([]uint32, len(s.v))
+	for i := range s.shift {
+		s.shift[i] = r.Uint32()
+	}
+}
+
+// Seek moves the sequence such that the next point is the one at index i.
+// It panics if i >= 2**32.
+func (s *Sobol) Seek(i uint64) {
+	if i >= 1<<32 {
+		panic("qmc: Sobol index out of range")
+	}
+	g := uint32(i ^ i>>1)
+	for j := range s.x {
+		var x uint32
+		for k, m := 0, g; m != 0; k, m = k+1, m>>1 {
+			if m&1 != 0 {
+				x ^= s.v[j][k]
+			}
+		}
+		s.x[j] = x
+	}
+	s.n = i
+}
+
+// NextVector fills dst, which must have length Dim(), with the next point in
+// the sequence. It panics after 2**32 points.
+func (s *Sobol) NextVector(dst []float64) {
+	if s.n >= 1<<32 {
+		panic("qmc: Sobol sequence exhausted")
+	}
+	for j, x := range s.x {
+		if s.seed != nil {
+			x = owenScramble(x, s.seed[j])
+		}
+		if s.shift != nil {
+			x ^= s.shift[j]
+		}
+		dst[j] = float64(x) * (1.0 / (1 << 32))
+	}
+	// Advance in Gray code order.
+	c := bits.TrailingZeros64(^s.n)
+	if c < 32 {
+		for j := range s.x {
+			s.x[j] ^= s.v[j][c]
+		}
+	}
+	s.n++
+}
+
+// owenScramble performs nested uniform scrambling of the bits of x.
+func owenScramble(x, seed uint32) uint32 {
+	x = bits.Reverse32(x)
+	x += seed
+	x ^= x * 0x6c50b47c
+	x ^= x * 0xb82f1e52
+	x ^= x * 0xc7afe638
+	x ^= x * 0x8d22f6e6
+	return bits.Reverse32(x)
+}
+
+// extendDirections returns the direction numbers in base followed by more for
+// each successive primitive polynomial, ordered by degree and then by
+// coefficients as in Joe and Kuo's table, until there are n in all. The
+// initial direction numbers of the new dimensions are odd and pseudo-random,
+// from a fixed seed so that they never change.
+func extendDirections(base []DirectionNumbers, n int) []DirectionNumbers {
+	dirs := append([]DirectionNumbers(nil), base...)
+	last := base[len(base)-1]
+	s, a := last.S, last.A
+	rng := splitmix(0x536f626f6c)
+	for len(dirs) < n {
+		a++
+		if a >= 1<<(s-1) {
+			s, a = s+1, 0
+		}
+		if !primitive(s, a) {
+			continue
+		}
+		m := make([]uint32, s)
+		for k := range m {
+			// m_k must be odd and less than 2**k, counting from 1.
+			m[k] = uint32(rng.next()%(1<<uint(k)))<<1 | 1
+		}
+		dirs = append(dirs, DirectionNumbers{S: s, A: a, M: m})
+	}
+	return dirs
+}
+
+// primitive reports whether the polynomial over GF(2) of degree s with
+// interior coefficients a, in the encoding of DirectionNumbers, is primitive,
+// i.e. whether x has multiplicative order exactly 2**s - 1 modulo it.
+func primitive(s, a uint32) bool {
+	p := uint64(1)<<s | uint64(a)<<1 | 1
+	e := uint64(1)<<s - 1
+	if polyPowX(e, p, s) != 1 {
+		return false
+	}
+	for q, r := uint64(2), e; r > 1; q++ {
+		if r%q != 0 {
+			continue
+		}
+		for r%q == 0 {
+			r /= q
+		}
+		if polyPowX(e/q, p, s) == 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// polyPowX computes x**e modulo the polynomial p of degree s > 1 over GF(2).
+func polyPowX(e, p uint64, s uint32) uint64 {
+	r, b := uint64(1), uint64(2)
+	for ; e > 0; e >>= 1 {
+		if e&1 != 0 {
+			r = polyMulMod(r, b, p, s)
+		}
+		b = polyMulMod(b, b, p, s)
+	}
+	return r
+}
+
+// polyMulMod multiplies x and y modulo the polynomial p of degree s over
+// GF(2). x and y must have degree less than s.
+func polyMulMod(x, y, p uint64, s uint32) uint64 {
+	var r uint64
+	for ; y != 0; y >>= 1 {
+		if y&1 != 0 {
+			r ^= x
+		}
+		x <<= 1
+		if x>>s&1 != 0 {
+			x ^= p
+		}
+	}
+	return r
+}
+
+// splitmix is the SplitMix64 generator, used for the fixed initial direction
+// numbers so that they do not depend on any Source in crazy.
+type splitmix uint64
+
+func (s *splitmix) next() uint64 {
+	*s += 0x9e3779b97f4a7c15
+	z := uint64(*s)
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
+
+// joeKuo holds the direction numbers for dimensions 2 through 21 from
+// new-joe-kuo-6.21201.
+var joeKuo = [...]DirectionNumbers{
+	{1, 0, []uint32{1}},
+	{2, 1, []uint32{1, 3}},
+	{3, 1, []uint32{1, 3, 1}},
+	{3, 2, []uint32{1, 1, 1}},
+	{4, 1, []uint32{1, 1, 3, 3}},
+	{4, 4, []uint32{1, 3, 5, 13}},
+	{5, 2, []uint32{1, 1, 5, 5, 17}},
+	{5, 4, []uint32{1, 1, 5, 5, 5}},
+	{5, 7, []uint32{1, 1, 7, 11, 19}},
+	{5, 11, []uint32{1, 1, 5, 1, 1}},
+	{5, 13, []uint32{1, 1, 1, 3, 11}},
+	{5, 14, []uint32{1, 3, 5, 5, 31}},
+	{6, 1, []uint32{1, 3, 3, 9, 7, 49}},
+	{6, 13, []uint32{1, 1, 1, 15, 21, 21}},
+	{6, 16, []uint32{1, 3, 1, 13, 27, 49}},
+	{6, 19, []uint32{1, 1, 1, 15, 7, 5}},
+	{6, 22, []uint32{1, 3, 1, 15, 13, 25}},
+	{6, 25, []uint32{1, 1, 5, 5, 19, 61}},
+	{7, 1, []uint32{1, 3, 7, 11, 23, 15, 103}},
+	{7, 4, []uint32{1, 3, 7, 13, 13, 15, 69}},
+}
@@ -0,0 +1,162 @@
+package qmc
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+func TestSobolPoints(t *testing.T) {
+	want := [][]float64{
+		{0, 0, 0},
+		{0.5, 0.5, 0.5},
+		{0.75, 0.25, 0.25},
+		{0.25, 0.75, 0.75},
+		{0.375, 0.375, 0.625},
+		{0.875, 0.875, 0.125},
+		{0.625, 0.125, 0.875},
+		{0.125, 0.625, 0.375},
+	}
+	s := NewSobol(3)
+	x := make([]float64, 3)
+	for i, w := range want {
+		s.NextVector(x)
+		for j := range w {
+			if x[j] != w[j] {
+				t.Fatalf("point %d is %v, want %v", i, x, w)
+			}
+		}
+	}
+}
+
+// testStratified checks that each of the first b**k points of s lies in a
+// different interval of width b**-k in each coordinate.
+func testStratified(t *testing.T, s Sequence, b, k int) {
+	t.Helper()
+	n := 1
+	for i := 0; i < k; i++ {
+		n *= b
+	}
+	seen := make([][]bool, s.Dim())
+	for j := range seen {
+		seen[j] = make([]bool, n)
+	}
+	x := make([]float64, s.Dim())
+	for i := 0; i < n; i++ {
+		s.NextVector(x)
+		for j, v := range x {
+			if v < 0 || v >= 1 {
+				t.Fatalf("point %d coordinate %d is %g", i, j, v)
+			}
+			c := int(v * float64(n))
+			if seen[j][c] {
+				t.Fatalf("point %d coordinate %d in occupied stratum %d", i, j, c)
+			}
+			seen[j][c] = true
+		}
+	}
+}
+
+func TestSobolStratified(t *testing.T) {
+	testStratified(t, NewSobol(SobolDims), 2, 12)
+}
+
+func TestSobolScrambled(t *testing.T) {
+	src := crazy.CryptoSeeded(crazy.NewXoshiro(), 32)
+	s := NewSobol(SobolDims)
+	s.Scramble(src)
+	testStratified(t, s, 2, 12)
+	s = NewSobol(SobolDims)
+	s.Shift(src)
+	testStratified(t, s, 2, 12)
+}
+
+func TestSobolSeek(t *testing.T) {
+	s := NewSobol(SobolDims)
+	s.Scramble(crazy.CryptoSeeded(crazy.NewXoshiro(), 32))
+	x := make([]float64, SobolDims)
+	y := make([]float64, SobolDims)
+	for i := 0; i < 1000; i++ {
+		s.NextVector(x)
+	}
+	s.NextVector(x)
+	s.Seek(1000)
+	s.NextVector(y)
+	for j := range x {
+		if x[j] != y[j] {
+			t.Fatalf("point after seek is %v, want %v", y, x)
+		}
+	}
+}
+
+func TestReadDirectionNumbers(t *testing.T) {
+	var b bytes.Buffer
+	b.WriteString("d       s       a       m_i\n")
+	for i, d := range joeKuo {
+		fmt.Fprintf(&b, "%d %d %d", i+2, d.S, d.A)
+		for _, m := range d.M {
+			fmt.Fprintf(&b, " %d", m)
+		}
+		b.WriteString("\n")
+	}
+	dirs, err := ReadDirectionNumbers(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) != len(joeKuo) {
+		t.Fatalf("read %d dimensions, want %d", len(dirs), len(joeKuo))
+	}
+	for i, d := range dirs {
+		w := joeKuo[i]
+		if d.S != w.S || d.A != w.A || len(d.M) != len(w.M) {
+			t.Fatalf("dimension %d is %v, want %v", i+2, d, w)
+		}
+	}
+	if _, err := ReadDirectionNumbers(strings.NewReader("2 1 0 1\n3 2 1 1\n")); err == nil {
+		t.Error("no error for missing direction numbers")
+	}
+}
+
+func TestSobolBuiltinDirections(t *testing.T) {
+	if len(sobolDirs) != SobolDims-1 {
+		t.Fatalf("%d built-in dimensions, want %d", len(sobolDirs)+1, SobolDims)
+	}
+	// The generated polynomials continue Joe and Kuo's order, so generating
+	// from the first entry alone must reproduce the rest of their table.
+	gen := extendDirections(joeKuo[:1], len(joeKuo))
+	for i, d := range gen {
+		if d.S != joeKuo[i].S || d.A != joeKuo[i].A {
+			t.Errorf("polynomial %d is (%d, %d), want (%d, %d)", i+2, d.S, d.A, joeKuo[i].S, joeKuo[i].A)
+		}
+	}
+	// Counts of primitive polynomials of degree 1 through 13.
+	count := []int{1, 1, 2, 2, 6, 6, 18, 16, 48, 60, 176, 144, 630}
+	deg := make([]int, len(count))
+	for i, d := range sobolDirs {
+		deg[d.S-1]++
+		if int(d.S) != len(d.M) {
+			t.Fatalf("dimension %d has degree %d but %d direction numbers", i+2, d.S, len(d.M))
+		}
+		for k, m := range d.M {
+			if m&1 == 0 || m >= 2<<uint(k) {
+				t.Fatalf("dimension %d has invalid direction number m_%d = %d", i+2, k+1, m)
+			}
+		}
+	}
+	for i := range count {
+		if deg[i] != count[i] {
+			t.Errorf("%d polynomials of degree %d, want %d", deg[i], i+1, count[i])
+		}
+	}
+}
+
+func BenchmarkSobol(b *testing.B) {
+	s := NewSobol(SobolDims)
+	x := make([]float64, SobolDims)
+	for i := 0; i < b.N; i++ {
+		s.NextVector(x)
+	}
+}
@@ -0,0 +1,14 @@
+/*
+Package randmat generates random matrices driven by crazy Sources.
+
+Orthogonal, Rotation, and Unitary produce matrices distributed according to
+the Haar measure on their respective groups. GOE, GUE, and Wishart produce
+matrices from the Gaussian orthogonal ensemble, the Gaussian unitary ensemble,
+and the Wishart distribution.
+
+All matrices are square and stored in row-major order in a slice, so that the
+element at row i and column j of an n×n matrix m is m[i*n+j]. Each function
+takes a destination slice which is reused if it has sufficient capacity and
+returns the filled matrix.
+*/
+package randmat
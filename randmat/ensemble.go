@@ -0,0 +1,90 @@
+package randmat
+
+import (
+	"math"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+// GOE fills dst with an n×n matrix from the Gaussian orthogonal ensemble. The
+// matrix is symmetric, with diagonal elements distributed N(0, 1) and
+// off-diagonal elements distributed N(0, 1/2).
+func GOE(src crazy.Source, n int, dst []float64) []float64 {
+	dst = resize(dst, n*n)
+	d := crazy.NewNormal(src, 0, 1)
+	s := math.Sqrt(0.5)
+	for i := 0; i < n; i++ {
+		dst[i*n+i] = d.Next()
+		for j := i + 1; j < n; j++ {
+			v := s * d.Next()
+			dst[i*n+j] = v
+			dst[j*n+i] = v
+		}
+	}
+	return dst
+}
+
+// GUE fills dst with an n×n matrix from the Gaussian unitary ensemble. The
+// matrix is Hermitian, with diagonal elements real and distributed N(0, 1)
+// and off-diagonal elements having real and imaginary parts independently
+// distributed N(0, 1/2).
+func GUE(src crazy.Source, n int, dst []complex128) []complex128 {
+	dst = resizec(dst, n*n)
+	d := crazy.NewNormal(src, 0, 1)
+	s := math.Sqrt(0.5)
+	for i := 0; i < n; i++ {
+		dst[i*n+i] = complex(d.Next(), 0)
+		for j := i + 1; j < n; j++ {
+			re, im := s*d.Next(), s*d.Next()
+			dst[i*n+j] = complex(re, im)
+			dst[j*n+i] = complex(re, -im)
+		}
+	}
+	return dst
+}
+
+// Wishart fills dst with a p×p matrix from the Wishart distribution with dof
+// degrees of freedom and scale matrix scale, i.e. the sum of outer products
+// of dof independent vectors distributed N(0, scale). If scale is nil, the
+// identity is used. It panics if scale is not positive definite.
+func Wishart(src crazy.Source, p, dof int, scale, dst []float64) []float64 {
+	dst = resize(dst, p*p)
+	d := crazy.NewNormal(src, 0, 1)
+	var l []float64
+	if scale != nil {
+		l = cholesky(scale, p)
+	}
+	for i := range dst {
+		dst[i] = 0
+	}
+	x := make([]float64, p)
+	y := make([]float64, p)
+	for k := 0; k < dof; k++ {
+		for i := range y {
+			y[i] = d.Next()
+		}
+		if l != nil {
+			// x = L y
+			for i := 0; i < p; i++ {
+				var s float64
+				for j := 0; j <= i; j++ {
+					s += l[i*p+j] * y[j]
+				}
+				x[i] = s
+			}
+		} else {
+			copy(x, y)
+		}
+		for i := 0; i < p; i++ {
+			for j := i; j < p; j++ {
+				dst[i*p+j] += x[i] * x[j]
+			}
+		}
+	}
+	for i := 0; i < p; i++ {
+		for j := 0; j < i; j++ {
+			dst[i*p+j] = dst[j*p+i]
+		}
+	}
+	return dst
+}
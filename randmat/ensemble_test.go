@@ -0,0 +1,84 @@
+package randmat
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+func TestGOE(t *testing.T) {
+	src := crazy.CryptoSeeded(crazy.NewXoshiro(), 32)
+	const n = 4
+	var h []float64
+	var d, o float64
+	trials := 1 << 15
+	for k := 0; k < trials; k++ {
+		h = GOE(src, n, h)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				if h[i*n+j] != h[j*n+i] {
+					t.Fatal("matrix not symmetric")
+				}
+			}
+		}
+		d += h[0] * h[0]
+		o += h[1] * h[1]
+	}
+	if d /= float64(trials); math.Abs(d-1) > 0.04 {
+		t.Errorf("diagonal variance %g", d)
+	}
+	if o /= float64(trials); math.Abs(o-0.5) > 0.02 {
+		t.Errorf("off-diagonal variance %g", o)
+	}
+}
+
+func TestGUE(t *testing.T) {
+	src := crazy.CryptoSeeded(crazy.NewXoshiro(), 32)
+	const n = 4
+	var h []complex128
+	for k := 0; k < 1024; k++ {
+		h = GUE(src, n, h)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				if h[i*n+j] != cmplx.Conj(h[j*n+i]) {
+					t.Fatal("matrix not Hermitian")
+				}
+			}
+		}
+	}
+}
+
+func TestWishart(t *testing.T) {
+	src := crazy.CryptoSeeded(crazy.NewXoshiro(), 32)
+	const p, dof = 3, 7
+	scale := []float64{
+		2, 0.5, 0,
+		0.5, 1, -0.3,
+		0, -0.3, 0.5,
+	}
+	m := make([]float64, p*p)
+	var w []float64
+	trials := 1 << 14
+	for k := 0; k < trials; k++ {
+		w = Wishart(src, p, dof, scale, w)
+		for i := range w {
+			m[i] += w[i]
+		}
+		if det(w, p) <= 0 {
+			t.Fatal("matrix not positive definite")
+		}
+	}
+	// E[W] = dof * scale; Var[W_ij] = dof * (scale_ij**2 + scale_ii scale_jj)
+	for i := 0; i < p; i++ {
+		for j := 0; j < p; j++ {
+			k := i*p + j
+			v := m[k] / float64(trials)
+			se := math.Sqrt(dof * (scale[k]*scale[k] + scale[i*p+i]*scale[j*p+j]) / float64(trials))
+			if math.Abs(v-dof*scale[k]) > 6*se {
+				t.Errorf("mean element (%d, %d) is %g, want %g", i, j, v, dof*scale[k])
+			}
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package randmat
+
+import "github.com/zephyrtronium/crazy"
+
+// Orthogonal fills dst with an n×n orthogonal matrix distributed according to
+// the Haar measure on O(n), using the QR decomposition of a matrix of normal
+// variates with the signs of R's diagonal corrected to be positive. See
+// Mezzadri, "How to generate random matrices from the classical compact
+// groups," Notices of the AMS 54(5), 2007.
+func Orthogonal(src crazy.Source, n int, dst []float64) []float64 {
+	dst = resize(dst, n*n)
+	d := crazy.NewNormal(src, 0, 1)
+	for i := range dst {
+		dst[i] = d.Next()
+	}
+	orthonormalize(dst, n)
+	return dst
+}
+
+// Rotation fills dst with an n×n rotation matrix distributed according to
+// the Haar measure on SO(n).
+func Rotation(src crazy.Source, n int, dst []float64) []float64 {
+	dst = Orthogonal(src, n, dst)
+	if det(dst, n) < 0 {
+		// Negating one column maps O(n) \ SO(n) onto SO(n) while preserving
+		// the Haar measure.
+		for i := 0; i < n; i++ {
+			dst[i*n] = -dst[i*n]
+		}
+	}
+	return dst
+}
+
+// Unitary fills dst with an n×n unitary matrix distributed according to the
+// Haar measure on U(n).
+func Unitary(src crazy.Source, n int, dst []complex128) []complex128 {
+	dst = resizec(dst, n*n)
+	d := crazy.NewNormal(src, 0, 1)
+	for i := range dst {
+		dst[i] = complex(d.Next(), d.Next())
+	}
+	orthonormalizec(dst, n)
+	return dst
+}
@@ -0,0 +1,102 @@
+package randmat
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/zephyrtronium/crazy"
+)
+
+// testOrthogonal checks that the n×n matrix q satisfies q^T q = I.
+func testOrthogonal(t *testing.T, q []float64, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			var d float64
+			for k := 0; k < n; k++ {
+				d += q[k*n+i] * q[k*n+j]
+			}
+			if i == j {
+				d--
+			}
+			if math.Abs(d) > 1e-12 {
+				t.Fatalf("(Q^T Q - I)[%d, %d] = %g", i, j, d)
+			}
+		}
+	}
+}
+
+func TestOrthogonal(t *testing.T) {
+	src := crazy.CryptoSeeded(crazy.NewXoshiro(), 32)
+	const n = 6
+	var q []float64
+	var m, s float64
+	neg := 0
+	trials := 1 << 14
+	for k := 0; k < trials; k++ {
+		q = Orthogonal(src, n, q)
+		testOrthogonal(t, q, n)
+		m += q[0]
+		s += q[0] * q[0]
+		if det(q, n) < 0 {
+			neg++
+		}
+	}
+	// Each element of a Haar orthogonal matrix has mean 0 and variance 1/n.
+	m /= float64(trials)
+	s /= float64(trials)
+	if math.Abs(m) > 0.016 || math.Abs(s-1.0/n) > 0.008 {
+		t.Errorf("element mean %g, second moment %g", m, s)
+	}
+	// Half should be reflections.
+	if f := float64(neg) / float64(trials); math.Abs(f-0.5) > 0.02 {
+		t.Errorf("fraction %g of matrices have negative determinant", f)
+	}
+}
+
+func TestRotation(t *testing.T) {
+	src := crazy.CryptoSeeded(crazy.NewXoshiro(), 32)
+	var q []float64
+	for n := 1; n <= 8; n++ {
+		for k := 0; k < 64; k++ {
+			q = Rotation(src, n, q)
+			testOrthogonal(t, q, n)
+			if d := det(q, n); math.Abs(d-1) > 1e-12 {
+				t.Fatalf("determinant %g", d)
+			}
+		}
+	}
+}
+
+func TestUnitary(t *testing.T) {
+	src := crazy.CryptoSeeded(crazy.NewXoshiro(), 32)
+	const n = 5
+	var u []complex128
+	for k := 0; k < 256; k++ {
+		u = Unitary(src, n, u)
+		for i := 0; i < n; i++ {
+			for j := 0; j < n; j++ {
+				var d complex128
+				for k := 0; k < n; k++ {
+					d += cmplx.Conj(u[k*n+i]) * u[k*n+j]
+				}
+				if i == j {
+					d--
+				}
+				if cmplx.Abs(d) > 1e-12 {
+					t.Fatalf("(U^H U - I)[%d, %d] = %g", i, j, d)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkOrthogonal(b *testing.B) {
+	src := crazy.CryptoSeeded(crazy.NewXoshiro(), 32)
+	q := make([]float64, 16*16)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Orthogonal(src, 16, q)
+	}
+}
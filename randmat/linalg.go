@@ -0,0 +1,132 @@
+package randmat
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// resize returns dst resliced to length n, allocating if needed.
+func resize(dst []float64, n int) []float64 {
+	if cap(dst) < n {
+		return make([]float64, n)
+	}
+	return dst[:n]
+}
+
+// resizec is resize for complex matrices.
+func resizec(dst []complex128, n int) []complex128 {
+	if cap(dst) < n {
+		return make([]complex128, n)
+	}
+	return dst[:n]
+}
+
+// orthonormalize applies modified Gram-Schmidt to the columns of the n×n
+// matrix a in place, twice for numerical stability. Because each column is
+// normalized to a positive length, this is equivalent to computing Q in a QR
+// decomposition where R has a positive diagonal.
+func orthonormalize(a []float64, n int) {
+	for pass := 0; pass < 2; pass++ {
+		for j := 0; j < n; j++ {
+			for k := 0; k < j; k++ {
+				var d float64
+				for i := 0; i < n; i++ {
+					d += a[i*n+k] * a[i*n+j]
+				}
+				for i := 0; i < n; i++ {
+					a[i*n+j] -= d * a[i*n+k]
+				}
+			}
+			var r float64
+			for i := 0; i < n; i++ {
+				r += a[i*n+j] * a[i*n+j]
+			}
+			r = 1 / math.Sqrt(r)
+			for i := 0; i < n; i++ {
+				a[i*n+j] *= r
+			}
+		}
+	}
+}
+
+// orthonormalizec is orthonormalize for complex matrices.
+func orthonormalizec(a []complex128, n int) {
+	for pass := 0; pass < 2; pass++ {
+		for j := 0; j < n; j++ {
+			for k := 0; k < j; k++ {
+				var d complex128
+				for i := 0; i < n; i++ {
+					d += cmplx.Conj(a[i*n+k]) * a[i*n+j]
+				}
+				for i := 0; i < n; i++ {
+					a[i*n+j] -= d * a[i*n+k]
+				}
+			}
+			var r float64
+			for i := 0; i < n; i++ {
+				v := a[i*n+j]
+				r += real(v)*real(v) + imag(v)*imag(v)
+			}
+			r = 1 / math.Sqrt(r)
+			for i := 0; i < n; i++ {
+				a[i*n+j] *= complex(r, 0)
+			}
+		}
+	}
+}
+
+// det computes the determinant of the n×n matrix a by LU decomposition with
+// partial pivoting. a is not modified.
+func det(a []float64, n int) float64 {
+	m := append([]float64(nil), a...)
+	d := 1.0
+	for k := 0; k < n; k++ {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(m[i*n+k]) > math.Abs(m[p*n+k]) {
+				p = i
+			}
+		}
+		if m[p*n+k] == 0 {
+			return 0
+		}
+		if p != k {
+			for j := 0; j < n; j++ {
+				m[k*n+j], m[p*n+j] = m[p*n+j], m[k*n+j]
+			}
+			d = -d
+		}
+		d *= m[k*n+k]
+		for i := k + 1; i < n; i++ {
+			f := m[i*n+k] / m[k*n+k]
+			for j := k + 1; j < n; j++ {
+				m[i*n+j] -= f * m[k*n+j]
+			}
+		}
+	}
+	return d
+}
+
+// cholesky computes the lower triangular Cholesky factor of the symmetric
+// positive definite n×n matrix a. It panics if a is not positive definite.
+func cholesky(a []float64, n int) []float64 {
+	l := make([]float64, n*n)
+	for j := 0; j < n; j++ {
+		s := a[j*n+j]
+		for k := 0; k < j; k++ {
+			s -= l[j*n+k] * l[j*n+k]
+		}
+		if !(s > 0) {
+			panic("randmat: matrix is not positive definite")
+		}
+		l[j*n+j] = math.Sqrt(s)
+		for i := j + 1; i < n; i++ {
+			s := a[i*n+j]
+			for k := 0; k < j; k++ {
+				s -= l[i*n+k] * l[j*n+k]
+			}
+			l[i*n+j] = s / l[j*n+j]
+		}
+	}
+	return l
+}
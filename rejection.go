@@ -0,0 +1,53 @@
+package crazy
+
+import "math"
+
+// Rejection produces variates of a target density by rejection sampling from
+// a proposal distribution. Bound must satisfy Target(x) <= Bound*ProposalPDF(x)
+// for all x; the expected number of proposals per variate is Bound when both
+// densities are normalized.
+type Rejection struct {
+	Source
+	// Target is the (possibly unnormalized) density of the desired
+	// distribution.
+	Target func(x float64) float64
+	// Proposal produces candidate variates.
+	Proposal Distribution
+	// ProposalPDF is the density of Proposal.
+	ProposalPDF func(x float64) float64
+	// Bound is the envelope constant.
+	Bound float64
+}
+
+// NewRejection creates a rejection sampler using src for acceptance tests.
+// If proposal implements Analytic, proposalPDF may be nil to use its PDF.
+func NewRejection(src Source, target func(x float64) float64, proposal Distribution, proposalPDF func(x float64) float64, bound float64) Rejection {
+	if proposalPDF == nil {
+		a, ok := proposal.(Analytic)
+		if !ok {
+			panic("crazy: rejection proposal has no PDF and is not Analytic")
+		}
+		proposalPDF = a.PDF
+	}
+	return Rejection{
+		Source:      src,
+		Target:      target,
+		Proposal:    proposal,
+		ProposalPDF: proposalPDF,
+		Bound:       bound,
+	}
+}
+
+// Next generates a variate.
+func (r Rejection) Next() float64 {
+	u := Uniform0_1{r.Source}
+	for {
+		x := r.Proposal.Next()
+		if u.Next()*r.Bound*r.ProposalPDF(x) < r.Target(x) {
+			return x
+		}
+		if failed(r.Source) {
+			return math.NaN()
+		}
+	}
+}
@@ -1,8 +1,9 @@
 package crazy
 
 import (
-	"encoding/binary"
+	"math"
 	"math/big"
+	"math/bits"
 )
 
 // RNG adapts a Source to produce integers.
@@ -12,42 +13,237 @@ type RNG struct {
 
 // Uint64 generates a random uint64.
 func (r RNG) Uint64() uint64 {
-	p := [8]byte{}
-	r.Read(p[:])
-	return binary.LittleEndian.Uint64(p[:])
+	return readUint(r.Source, 8)
 }
 
 // Uint32 generates a random uint32.
 func (r RNG) Uint32() uint32 {
-	p := [4]byte{}
-	r.Read(p[:])
-	return binary.LittleEndian.Uint32(p[:])
+	return uint32(readUint(r.Source, 4))
 }
 
 // Uint16 generates a random uint16.
 func (r RNG) Uint16() uint16 {
-	p := [2]byte{}
-	r.Read(p[:])
-	return binary.LittleEndian.Uint16(p[:])
+	return uint16(readUint(r.Source, 2))
+}
+
+// Uint64n generates a random uint64 in the interval [0, max). It uses Lemire's
+// multiply-shift method, which needs a division only when the first attempt
+// lands in the small region that would bias the result. See Lemire, "Fast
+// random integer generation in an interval," ACM TOMACS 29(1), 2019. It panics
+// if max == 0.
+func (r RNG) Uint64n(max uint64) uint64 {
+	if max == 0 {
+		panic("maximum zero")
+	}
+	hi, lo := bits.Mul64(r.Uint64(), max)
+	if lo < max {
+		t := -max % max
+		// A failed CheckedRNG produces only zeros, which would never leave
+		// the loop.
+		for lo < t && !failed(r.Source) {
+			hi, lo = bits.Mul64(r.Uint64(), max)
+		}
+	}
+	return hi
+}
+
+// Uint32n generates a random uint32 in the interval [0, max). It panics if
+// max == 0.
+func (r RNG) Uint32n(max uint32) uint32 {
+	if max == 0 {
+		panic("maximum zero")
+	}
+	m := uint64(r.Uint32()) * uint64(max)
+	if uint32(m) < max {
+		t := -max % max
+		for uint32(m) < t && !failed(r.Source) {
+			m = uint64(r.Uint32()) * uint64(max)
+		}
+	}
+	return uint32(m >> 32)
+}
+
+// Int64n generates a random int64 in the interval [0, max). It panics if
+// max <= 0.
+func (r RNG) Int64n(max int64) int64 {
+	if max <= 0 {
+		panic("maximum zero or below")
+	}
+	return int64(r.Uint64n(uint64(max)))
+}
+
+// Int32n generates a random int32 in the interval [0, max). It panics if
+// max <= 0.
+func (r RNG) Int32n(max int32) int32 {
+	if max <= 0 {
+		panic("maximum zero or below")
+	}
+	return int32(r.Uint32n(uint32(max)))
 }
 
 // Uintn generates a random uint in the interval [0, max). It panics if
 // max == 0.
 func (r RNG) Uintn(max uint) uint {
-	bad := ^uint(0) - ^uint(0)%max
-	x := uint(r.Uint64())
-	for x > bad {
-		x = uint(r.Uint64())
-	}
-	return x % max
+	return uint(r.Uint64n(uint64(max)))
 }
 
 // Intn generates a random int in the interval [0, max). It panics if max <= 0.
 func (r RNG) Intn(max int) int {
-	if max < 0 {
-		panic("maximum below zero")
+	if max <= 0 {
+		panic("maximum zero or below")
+	}
+	return int(r.Uint64n(uint64(max)))
+}
+
+// IntRange generates a random int in the interval [lo, hi). The interval may
+// be as wide as the full range of int. It panics if hi <= lo.
+func (r RNG) IntRange(lo, hi int) int {
+	if hi <= lo {
+		panic("empty range")
+	}
+	// The width is computed with unsigned wraparound, so it is correct even
+	// when hi-lo overflows int.
+	return lo + int(r.Uint64n(uint64(hi)-uint64(lo)))
+}
+
+// Float64 generates a random float64 in the interval [0, 1) with 53 bits of
+// precision. It produces the same values as Uniform0_1.
+func (r RNG) Float64() float64 {
+	return Uniform0_1{r.Source}.Next()
+}
+
+// Float32 generates a random float32 in the interval [0, 1) with 24 bits of
+// precision.
+func (r RNG) Float32() float32 {
+	b := readUint(r.Source, 4)
+	if failed(r.Source) {
+		return float32(math.NaN())
+	}
+	return float32(b>>8) * 0x1p-24
+}
+
+// Float64Open generates a random float64 in the open interval (0, 1). The
+// results are the midpoints of 2**52 equal subintervals, so the distribution
+// is symmetric about 1/2.
+func (r RNG) Float64Open() float64 {
+	b := readUint(r.Source, 8)
+	if failed(r.Source) {
+		return math.NaN()
+	}
+	return (float64(b>>12) + 0.5) * 0x1p-52
+}
+
+// NormFloat64 generates a standard normal variate.
+func (r RNG) NormFloat64() float64 {
+	return normalZig.GenNext(r.Source)
+}
+
+// ExpFloat64 generates an exponential variate with rate 1.
+func (r RNG) ExpFloat64() float64 {
+	return expoZig.GenNext(r.Source)
+}
+
+// Bits generates a random integer with n bits. It panics if n > 64.
+//
+// Only a BufferedRNG keeps the bits a call does not use; RNG holds no state of
+// its own. With any other Source, each call draws a whole value: one Uint64
+// from a Uint64Source, which includes every PRNG in this package, or (n+7)/8
+// bytes from other Sources. Wrap the Source with NewBufferedRNG to save bits.
+func (r RNG) Bits(n uint) uint64 {
+	if n > 64 {
+		panic("bit count above 64")
+	}
+	return readBits(r.Source, n)
+}
+
+// Bool generates a random bool. It consumes one bit if the Source is a
+// BufferedRNG; otherwise it discards the rest of a whole value as described
+// for Bits.
+func (r RNG) Bool() bool {
+	return readBits(r.Source, 1) != 0
+}
+
+// Bernoulli returns true with probability p, which is clamped to [0, 1]. It
+// compares random bits against the binary expansion of p lazily, so it is
+// exact for every float64 p and needs two bits on average. Those are two bits
+// of a BufferedRNG's stream; with other Sources, each call spends a whole
+// value as described for Bits.
+func (r RNG) Bernoulli(p float64) bool {
+	if !(p > 0) {
+		return false
+	}
+	if p >= 1 {
+		return true
+	}
+	bits := bitReader{src: r.Source}
+	// Each iteration extracts the next binary digit of p, which is exact
+	// because doubling and subtracting 1 lose nothing for p in (0, 1).
+	for p > 0 {
+		p *= 2
+		d := p >= 1
+		if d {
+			p--
+		}
+		if u := bits.bit(); u != d {
+			// The first differing digit decides whether U < p.
+			return d
+		}
+	}
+	return false
+}
+
+// BernoulliRat returns true with probability exactly num/den, which is clamped
+// to at most 1. It uses the same lazy comparison as Bernoulli, generating the
+// binary digits of num/den by long division. Like Bernoulli, it saves unused
+// bits only with a BufferedRNG. It panics if den == 0.
+func (r RNG) BernoulliRat(num, den uint64) bool {
+	if den == 0 {
+		panic("denominator zero")
+	}
+	if num >= den {
+		return true
+	}
+	bits := bitReader{src: r.Source}
+	for rem := num; rem != 0; {
+		// Doubling rem may overflow, so compare it against den-rem instead.
+		d := rem >= den-rem
+		if d {
+			rem -= den - rem
+		} else {
+			rem *= 2
+		}
+		if u := bits.bit(); u != d {
+			return d
+		}
+	}
+	return false
+}
+
+// bitReader produces single random bits for one call of Bernoulli or
+// BernoulliRat. It defers to a BufferedRNG's own bit buffer when it has one.
+// Otherwise it draws a 64-bit word when it needs a bit, and whatever bits of
+// that word it has not used when the call ends are lost.
+type bitReader struct {
+	src Source
+	w   uint64
+	n   uint
+}
+
+func (b *bitReader) bit() bool {
+	switch s := b.src.(type) {
+	case *BufferedRNG:
+		return s.Bits(1) != 0
+	case RNG:
+		b.src = s.Source
+		return b.bit()
+	}
+	if b.n == 0 {
+		b.w, b.n = readUint(b.src, 8), 64
 	}
-	return int(r.Uintn(uint(max)))
+	u := b.w&1 != 0
+	b.w >>= 1
+	b.n--
+	return u
 }
 
 // Big generates a random number with maximum bit length nbits.
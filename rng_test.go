@@ -1,6 +1,8 @@
 package crazy
 
 import (
+	"fmt"
+	"math"
 	"math/big"
 	"testing"
 )
@@ -32,3 +34,309 @@ func TestBign(t *testing.T) {
 		}
 	}
 }
+
+func TestBoundedUniform(t *testing.T) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	const n, k = 70000, 7
+	cases := []struct {
+		name string
+		f    func() int
+	}{
+		{"Uint64n", func() int { return int(r.Uint64n(k)) }},
+		{"Uint32n", func() int { return int(r.Uint32n(k)) }},
+		{"Int64n", func() int { return int(r.Int64n(k)) }},
+		{"Int32n", func() int { return int(r.Int32n(k)) }},
+		{"Intn", func() int { return r.Intn(k) }},
+		{"IntRange", func() int { return r.IntRange(-3, 4) + 3 }},
+	}
+	for _, c := range cases {
+		counts := make([]int, k)
+		for i := 0; i < n; i++ {
+			v := c.f()
+			if v < 0 || v >= k {
+				t.Fatalf("%s gave %d, out of range", c.name, v)
+			}
+			counts[v]++
+		}
+		chi2 := chi2Uniform(counts, k)
+		// The 99.99th percentile of chi-squared with 6 degrees of freedom is
+		// about 27.9.
+		if chi2 > 28 {
+			t.Errorf("%s is not uniform: chi2 = %v, counts %v", c.name, chi2, counts)
+		}
+	}
+}
+
+func TestUint64nLarge(t *testing.T) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	// With max just above 2**63, nearly half of all 64-bit values are in the
+	// biased region, so rejection is exercised constantly.
+	max := uint64(1)<<63 + 1
+	var high int
+	const n = 100000
+	for i := 0; i < n; i++ {
+		v := r.Uint64n(max)
+		if v >= max {
+			t.Fatalf("Uint64n(%d) gave %d", max, v)
+		}
+		if v >= max/2 {
+			high++
+		}
+	}
+	if d := float64(high) - n/2; math.Abs(d) > 5*math.Sqrt(n/4) {
+		t.Errorf("%d of %d values in upper half", high, n)
+	}
+}
+
+func TestIntRangeExtremes(t *testing.T) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	var neg, pos bool
+	for i := 0; i < 1000; i++ {
+		v := r.IntRange(math.MinInt, math.MaxInt)
+		if v == math.MaxInt {
+			t.Fatalf("IntRange gave upper bound")
+		}
+		neg = neg || v < 0
+		pos = pos || v > 0
+	}
+	if !neg || !pos {
+		t.Errorf("full-width IntRange did not cover both signs")
+	}
+	if v := r.IntRange(5, 6); v != 5 {
+		t.Errorf("IntRange(5, 6) gave %d", v)
+	}
+}
+
+func TestBoundedPanics(t *testing.T) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	cases := map[string]func(){
+		"Uint64n(0)":      func() { r.Uint64n(0) },
+		"Uint32n(0)":      func() { r.Uint32n(0) },
+		"Uintn(0)":        func() { r.Uintn(0) },
+		"Int64n(-1)":      func() { r.Int64n(-1) },
+		"Int32n(0)":       func() { r.Int32n(0) },
+		"Intn(0)":         func() { r.Intn(0) },
+		"IntRange(1, 1)":  func() { r.IntRange(1, 1) },
+		"IntRange(2, -2)": func() { r.IntRange(2, -2) },
+	}
+	for name, f := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestRNGFloats(t *testing.T) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	n := 1 << 22
+	if testing.Short() {
+		n = 1 << 18
+	}
+	cases := []struct {
+		name      string
+		f         func() float64
+		lo, hi    float64
+		open      bool
+		mean, vrc float64
+	}{
+		{"Float64", r.Float64, 0, 1, false, 0.5, 1.0 / 12},
+		{"Float32", func() float64 { return float64(r.Float32()) }, 0, 1, false, 0.5, 1.0 / 12},
+		{"Float64Open", r.Float64Open, 0, 1, true, 0.5, 1.0 / 12},
+		{"NormFloat64", r.NormFloat64, math.Inf(-1), math.Inf(1), true, 0, 1},
+		{"ExpFloat64", r.ExpFloat64, 0, math.Inf(1), false, 1, 1},
+	}
+	for _, c := range cases {
+		var m, s float64
+		for i := 0; i < n; i++ {
+			x := c.f()
+			if x < c.lo || x >= c.hi || c.open && x == c.lo {
+				t.Fatalf("%s gave %v out of range", c.name, x)
+			}
+			m += x
+			s += x * x
+		}
+		m /= float64(n)
+		s = s/float64(n) - m*m
+		if se := math.Sqrt(c.vrc / float64(n)); math.Abs(m-c.mean) > 6*se {
+			t.Errorf("%s sample mean %g, expected %g", c.name, m, c.mean)
+		}
+		if math.Abs(s-c.vrc) > 0.02*c.vrc {
+			t.Errorf("%s sample variance %g, expected %g", c.name, s, c.vrc)
+		}
+	}
+}
+
+func TestRNGFloatsMatchDistributions(t *testing.T) {
+	a := NewXoshiro()
+	a.Seed(4)
+	b := a.Copy()
+	r := RNG{a}
+	for i := 0; i < 1000; i++ {
+		if x, y := r.Float64(), (Uniform0_1{b}).Next(); x != y {
+			t.Fatalf("Float64 gave %v, Uniform0_1 gave %v", x, y)
+		}
+		if x, y := r.NormFloat64(), (Normal{b, 0, 1}).Next(); x != y {
+			t.Fatalf("NormFloat64 gave %v, Normal gave %v", x, y)
+		}
+		if x, y := r.ExpFloat64(), (Exponential{b, 1}).Next(); x != y {
+			t.Fatalf("ExpFloat64 gave %v, Exponential gave %v", x, y)
+		}
+	}
+}
+
+func TestFloat64OpenEndpoints(t *testing.T) {
+	lo, hi := words{0}, words{^uint64(0)}
+	if x := (RNG{&lo}).Float64Open(); x <= 0 {
+		t.Errorf("Float64Open gave %v for the zero word", x)
+	}
+	if x := (RNG{&hi}).Float64Open(); x >= 1 {
+		t.Errorf("Float64Open gave %v for the largest word", x)
+	}
+}
+
+func TestBernoulli(t *testing.T) {
+	const n = 200000
+	srcs := map[string]Source{
+		"RNG":      CryptoSeeded(NewXoshiro(), 32),
+		"Buffered": NewBufferedRNG(CryptoSeeded(NewXoshiro(), 32), 0),
+	}
+	for name, src := range srcs {
+		r := RNG{src}
+		for _, p := range []float64{1e-3, 0.1, 1.0 / 3, 0.5, 0.999} {
+			var k int
+			for i := 0; i < n; i++ {
+				if r.Bernoulli(p) {
+					k++
+				}
+			}
+			if d := float64(k) - n*p; math.Abs(d) > 6*math.Sqrt(n*p*(1-p)) {
+				t.Errorf("%s Bernoulli(%v) gave %d of %d", name, p, k, n)
+			}
+		}
+		rats := [][2]uint64{{1, 3}, {2, 7}, {1, 1000}, {^uint64(0) - 1, ^uint64(0)}, {1 << 63, ^uint64(0)}}
+		for _, c := range rats {
+			p := float64(c[0]) / float64(c[1])
+			var k int
+			for i := 0; i < n; i++ {
+				if r.BernoulliRat(c[0], c[1]) {
+					k++
+				}
+			}
+			if d := float64(k) - n*p; math.Abs(d) > 6*math.Sqrt(n*p*(1-p))+1 {
+				t.Errorf("%s BernoulliRat(%d, %d) gave %d of %d", name, c[0], c[1], k, n)
+			}
+		}
+	}
+}
+
+func TestBernoulliEdges(t *testing.T) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	for i := 0; i < 1000; i++ {
+		if r.Bernoulli(0) || r.Bernoulli(-1) || r.Bernoulli(math.NaN()) {
+			t.Fatal("Bernoulli true with probability 0")
+		}
+		if !r.Bernoulli(1) || !r.Bernoulli(2) {
+			t.Fatal("Bernoulli false with probability 1")
+		}
+		if r.BernoulliRat(0, 5) || !r.BernoulliRat(5, 5) || !r.BernoulliRat(6, 5) {
+			t.Fatal("BernoulliRat wrong at an endpoint")
+		}
+		// The smallest positive float64 is a success only if the first 1074
+		// bits are all zero.
+		if r.Bernoulli(math.SmallestNonzeroFloat64) {
+			t.Fatal("Bernoulli true with probability 2**-1074")
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("BernoulliRat(1, 0) did not panic")
+		}
+	}()
+	r.BernoulliRat(1, 0)
+}
+
+func TestBernoulliBitCost(t *testing.T) {
+	b := NewBufferedRNG(CryptoSeeded(NewXoshiro(), 32), 1<<20)
+	b.Bool()
+	before := b.Buffered()
+	const n = 100000
+	var k int
+	for i := 0; i < n; i++ {
+		if b.Bool() {
+			k++
+		}
+		b.Bernoulli(0.3)
+		b.BernoulliRat(1, 3)
+	}
+	// One bit for Bool and two on average for each Bernoulli.
+	used := float64(before-b.Buffered()) * 8
+	if used < 4.5*n || used > 5.5*n {
+		t.Errorf("used %v bits for %d rounds, want about %d", used, n, 5*n)
+	}
+	if d := float64(k) - n/2; math.Abs(d) > 6*math.Sqrt(n/4) {
+		t.Errorf("Bool gave %d true of %d", k, n)
+	}
+}
+
+func TestRNGBits(t *testing.T) {
+	w := words{0x0123456789abcdef}
+	r := RNG{&w}
+	if x := r.Bits(12); x != 0xdef {
+		t.Errorf("Bits(12) gave %x, want def", x)
+	}
+	if x := r.Bits(0); x != 0 {
+		t.Errorf("Bits(0) gave %x", x)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Bits(65) did not panic")
+		}
+	}()
+	r.Bits(65)
+}
+
+// uintnModulo is the previous implementation of Uintn, kept for comparison.
+func uintnModulo(r RNG, max uint) uint {
+	bad := ^uint(0) - ^uint(0)%max
+	x := uint(r.Uint64())
+	for x > bad {
+		x = uint(r.Uint64())
+	}
+	return x % max
+}
+
+var boundedSink uint
+
+func BenchmarkUintn(b *testing.B) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	for _, max := range []uint{6, 1e9 + 7, 1<<63 + 1} {
+		b.Run(fmt.Sprint(max), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				boundedSink += r.Uintn(max)
+			}
+		})
+	}
+}
+
+func BenchmarkUintnModulo(b *testing.B) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	for _, max := range []uint{6, 1e9 + 7, 1<<63 + 1} {
+		b.Run(fmt.Sprint(max), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				boundedSink += uintnModulo(r, max)
+			}
+		})
+	}
+}
+
+func BenchmarkUint32n(b *testing.B) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	for i := 0; i < b.N; i++ {
+		boundedSink += uint(r.Uint32n(1e9 + 7))
+	}
+}
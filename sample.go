@@ -0,0 +1,182 @@
+package crazy
+
+import (
+	"container/heap"
+	"math"
+)
+
+// Sample selects k distinct integers uniformly at random from [0, n) and
+// returns them in random order. It uses Floyd's algorithm, which needs only k
+// random numbers, unless k is a large fraction of n, in which case it
+// partially shuffles the whole range. It panics if k is negative or greater
+// than n.
+func Sample(n, k int, rng RNG) []int {
+	if k < 0 || k > n {
+		panic("crazy: sample size out of range")
+	}
+	if k > n/4 {
+		p := make([]int, n)
+		for i := range p {
+			p[i] = i
+		}
+		PartialShuffle(p, k, rng)
+		return p[:k:k]
+	}
+	// Floyd, "A sample of brilliance," Comm. ACM 30(9), 1987.
+	s := make([]int, 0, k)
+	seen := make(map[int]struct{}, k)
+	for j := n - k; j < n; j++ {
+		t := rng.Intn(j + 1)
+		if _, ok := seen[t]; ok {
+			t = j
+		}
+		seen[t] = struct{}{}
+		s = append(s, t)
+	}
+	// Floyd's algorithm selects a uniform set, but not in uniform order.
+	ShuffleSlice(s, rng)
+	return s
+}
+
+// SampleSlice returns a new slice containing k distinct elements of data
+// chosen uniformly at random, in random order. It panics if k is negative or
+// greater than len(data).
+func SampleSlice[T any](data []T, k int, rng RNG) []T {
+	r := make([]T, k)
+	for i, j := range Sample(len(data), k, rng) {
+		r[i] = data[j]
+	}
+	return r
+}
+
+// WeightedSample selects k distinct indices of weights without replacement,
+// each draw choosing among the remaining indices with probability
+// proportional to their weights, and returns them in the order drawn. Indices
+// with zero weight are never selected, so fewer than k indices are returned if
+// fewer than k weights are positive. It uses the A-ES algorithm of Efraimidis
+// and Spirakis, "Weighted random sampling with a reservoir," Inf. Process.
+// Lett. 97(5), 2006, which needs one random number per weight. It panics if
+// any weight is negative or NaN.
+func WeightedSample(weights []float64, k int, rng RNG) []int {
+	e := NewExponential(rng.Source, 1)
+	h := make(keyHeap, 0, k)
+	for i, w := range weights {
+		if !(w >= 0) {
+			panic("crazy: invalid sample weight")
+		}
+		if w == 0 || k == 0 {
+			continue
+		}
+		// The item with the largest u**(1/w) is drawn first. Equivalently,
+		// the smallest E/w for standard exponential E.
+		key := -e.Next() / w
+		if len(h) < k {
+			heap.Push(&h, keyed{key, i})
+		} else if key > h[0].key {
+			h[0] = keyed{key, i}
+			heap.Fix(&h, 0)
+		}
+	}
+	r := make([]int, len(h))
+	for i := len(r) - 1; i >= 0; i-- {
+		r[i] = heap.Pop(&h).(keyed).i
+	}
+	return r
+}
+
+type keyed struct {
+	key float64
+	i   int
+}
+
+// keyHeap is a min-heap of keys.
+type keyHeap []keyed
+
+func (h keyHeap) Len() int            { return len(h) }
+func (h keyHeap) Less(i, j int) bool  { return h[i].key < h[j].key }
+func (h keyHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *keyHeap) Push(x interface{}) { *h = append(*h, x.(keyed)) }
+func (h *keyHeap) Pop() interface{} {
+	x := (*h)[len(*h)-1]
+	*h = (*h)[:len(*h)-1]
+	return x
+}
+
+// Reservoir maintains a uniform random sample of fixed size from a stream of
+// unknown length. It uses Algorithm L of Li, "Reservoir-sampling algorithms of
+// time complexity O(n(1 + log(N/n)))," ACM TOMS 20(4), 1994, which needs
+// random numbers only for the items that enter the sample.
+//
+// Reservoir must be used through a pointer, and it is not safe for concurrent
+// use.
+type Reservoir[T any] struct {
+	rng   RNG
+	items []T
+	k     int
+	// n is the number of items seen, next is the 1-based index of the next
+	// item to enter the sample, and w is the running threshold.
+	n, next int64
+	w       float64
+}
+
+// NewReservoir creates a reservoir holding up to k items, drawing from the
+// specified RNG. It panics if k is negative.
+func NewReservoir[T any](k int, rng RNG) *Reservoir[T] {
+	if k < 0 {
+		panic("crazy: negative reservoir size")
+	}
+	return &Reservoir[T]{rng: rng, items: make([]T, 0, k), k: k}
+}
+
+// Add offers an item to the reservoir.
+func (r *Reservoir[T]) Add(x T) {
+	r.n++
+	if r.n <= int64(r.k) {
+		r.items = append(r.items, x)
+		if r.n == int64(r.k) {
+			r.w = math.Exp(-r.exp() / float64(r.k))
+			r.skip()
+		}
+		return
+	}
+	if r.n != r.next {
+		return
+	}
+	r.items[r.rng.Intn(r.k)] = x
+	r.w *= math.Exp(-r.exp() / float64(r.k))
+	r.skip()
+}
+
+// Consume offers every item of seq to the reservoir. seq has the same shape as
+// an iter.Seq[T], so on Go 1.23 and later any such iterator can be passed.
+func (r *Reservoir[T]) Consume(seq func(yield func(T) bool)) {
+	seq(func(x T) bool {
+		r.Add(x)
+		return true
+	})
+}
+
+// Items returns a copy of the current sample, which holds min(k, N()) items.
+func (r *Reservoir[T]) Items() []T {
+	return append([]T(nil), r.items...)
+}
+
+// N returns the number of items offered to the reservoir.
+func (r *Reservoir[T]) N() int64 {
+	return r.n
+}
+
+// exp generates a standard exponential variate, i.e. -log(u) for uniform u.
+func (r *Reservoir[T]) exp() float64 {
+	return NewExponential(r.rng.Source, 1).Next()
+}
+
+// skip sets the index of the next item to enter the sample.
+func (r *Reservoir[T]) skip() {
+	s := math.Floor(-r.exp() / math.Log1p(-r.w))
+	if !(s < float64(math.MaxInt64-r.n-1)) {
+		r.next = math.MaxInt64
+		return
+	}
+	r.next = r.n + int64(s) + 1
+}
@@ -0,0 +1,137 @@
+package crazy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSample(t *testing.T) {
+	rng := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	// Ordered pairs from n = 20 use Floyd's algorithm; from n = 5, partial
+	// shuffling.
+	for _, n := range []int{20, 5} {
+		const trials = 40000
+		obs := make(map[[2]int]int)
+		for i := 0; i < trials; i++ {
+			s := Sample(n, 2, rng)
+			if len(s) != 2 || s[0] == s[1] || s[0] < 0 || s[1] >= n || s[1] < 0 || s[0] >= n {
+				t.Fatalf("bad sample %v from %d", s, n)
+			}
+			obs[[2]int{s[0], s[1]}]++
+		}
+		want := make(map[[2]int]float64)
+		for a := 0; a < n; a++ {
+			for b := 0; b < n; b++ {
+				if a != b {
+					want[[2]int{a, b}] = trials / float64(n*(n-1))
+				}
+			}
+		}
+		df := float64(len(want) - 1)
+		if c := chi2(obs, want); c > df+5*math.Sqrt(2*df) {
+			t.Errorf("ordered pairs from %d are not uniform: chi2 = %v with %v df", n, c, df)
+		}
+	}
+	if s := Sample(10, 10, rng); len(s) != 10 {
+		t.Errorf("full sample has length %d", len(s))
+	}
+	if s := Sample(10, 0, rng); len(s) != 0 {
+		t.Errorf("empty sample has length %d", len(s))
+	}
+}
+
+func TestSampleSlice(t *testing.T) {
+	rng := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	data := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m", "n", "o", "p"}
+	s := SampleSlice(data, 3, rng)
+	seen := make(map[string]bool)
+	for _, v := range s {
+		if seen[v] {
+			t.Errorf("sample %v has duplicates", s)
+		}
+		seen[v] = true
+	}
+}
+
+func TestWeightedSample(t *testing.T) {
+	rng := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	w := []float64{1, 2, 0, 3, 4}
+	const trials = 40000
+	obs := make(map[[2]int]int)
+	for i := 0; i < trials; i++ {
+		s := WeightedSample(w, 2, rng)
+		if len(s) != 2 || s[0] == s[1] {
+			t.Fatalf("bad sample %v", s)
+		}
+		obs[[2]int{s[0], s[1]}]++
+	}
+	want := make(map[[2]int]float64)
+	for a, wa := range w {
+		for b, wb := range w {
+			if a != b && wa > 0 && wb > 0 {
+				want[[2]int{a, b}] = trials * wa / 10 * wb / (10 - wa)
+			}
+		}
+	}
+	for k := range obs {
+		if _, ok := want[k]; !ok {
+			t.Fatalf("impossible pair %v selected", k)
+		}
+	}
+	df := float64(len(want) - 1)
+	if c := chi2(obs, want); c > df+5*math.Sqrt(2*df) {
+		t.Errorf("pairs are not distributed correctly: chi2 = %v with %v df", c, df)
+	}
+	if s := WeightedSample(w, 10, rng); len(s) != 4 {
+		t.Errorf("oversized sample %v should have the 4 positive-weight indices", s)
+	}
+}
+
+func TestReservoir(t *testing.T) {
+	rng := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	const n, k, trials = 50, 5, 20000
+	counts := make([]int, n)
+	seq := func(yield func(int) bool) {
+		for i := 0; i < n; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	for i := 0; i < trials; i++ {
+		r := NewReservoir[int](k, rng)
+		r.Consume(seq)
+		if r.N() != n {
+			t.Fatalf("reservoir saw %d items, want %d", r.N(), n)
+		}
+		items := r.Items()
+		if len(items) != k {
+			t.Fatalf("reservoir has %d items, want %d", len(items), k)
+		}
+		for _, v := range items {
+			counts[v]++
+		}
+	}
+	// Each item is included with probability k/n.
+	p := float64(k) / n
+	se := math.Sqrt(trials * p * (1 - p))
+	for i, c := range counts {
+		if math.Abs(float64(c)-trials*p) > 5*se {
+			t.Errorf("item %d included %d times, want about %v", i, c, trials*p)
+		}
+	}
+}
+
+func TestReservoirShort(t *testing.T) {
+	r := NewReservoir[string](4, RNG{CryptoSeeded(NewXoshiro(), 32)})
+	r.Add("a")
+	r.Add("b")
+	if items := r.Items(); len(items) != 2 || items[0] != "a" || items[1] != "b" {
+		t.Errorf("short stream gave %v", items)
+	}
+	z := NewReservoir[int](0, RNG{CryptoSeeded(NewXoshiro(), 32)})
+	z.Add(1)
+	if len(z.Items()) != 0 || z.N() != 1 {
+		t.Errorf("empty reservoir has %v after %d items", z.Items(), z.N())
+	}
+}
@@ -0,0 +1,129 @@
+package crazy
+
+import "math"
+
+// SkewNormal adapts a Source to produce random numbers under a skew-normal
+// distribution. Shape controls skewness, with zero giving a normal
+// distribution; Mu and Sigma are the location and scale.
+type SkewNormal struct {
+	Source
+	Shape, Mu, Sigma float64
+}
+
+// NewSkewNormal creates a skew-normal distribution drawing from the
+// specified source with given shape, location, and scale. It panics if scale
+// is not positive.
+func NewSkewNormal(src Source, shape, loc, scale float64) SkewNormal {
+	if !(scale > 0) {
+		panic("crazy: skew-normal scale not positive")
+	}
+	return SkewNormal{
+		Source: src,
+		Shape:  shape,
+		Mu:     loc,
+		Sigma:  scale,
+	}
+}
+
+// delta returns Shape / sqrt(1 + Shape**2).
+func (s SkewNormal) delta() float64 {
+	return s.Shape / math.Sqrt(1+s.Shape*s.Shape)
+}
+
+// Next generates a skew-normal variate.
+func (s SkewNormal) Next() float64 {
+	d := s.delta()
+	u := normalZig.GenNext(s.Source)
+	v := normalZig.GenNext(s.Source)
+	x := d*u + math.Sqrt(1-d*d)*v
+	if u < 0 {
+		x = -x
+	}
+	return s.Mu + s.Sigma*x
+}
+
+// PDF evaluates the probability density function at x.
+func (s SkewNormal) PDF(x float64) float64 {
+	z := (x - s.Mu) / s.Sigma
+	return 2 / s.Sigma * math.Exp(-0.5*z*z) / math.Sqrt(2*math.Pi) * stdNormalCDF(s.Shape*z)
+}
+
+// CDF evaluates the cumulative distribution function at x.
+func (s SkewNormal) CDF(x float64) float64 {
+	z := (x - s.Mu) / s.Sigma
+	p := stdNormalCDF(z) - 2*owenT(z, s.Shape)
+	// Rounding can push the result slightly outside [0, 1] in the tails.
+	return math.Max(0, math.Min(1, p))
+}
+
+// Quantile evaluates the inverse of the CDF at p. There is no closed form, so
+// it is computed numerically.
+func (s SkewNormal) Quantile(p float64) float64 {
+	return solveQuantile(s.CDF, s.PDF, p, s.Mean(), s.Sigma)
+}
+
+// Mean returns the expected value of the distribution.
+func (s SkewNormal) Mean() float64 {
+	return s.Mu + s.Sigma*s.delta()*math.Sqrt(2/math.Pi)
+}
+
+// Variance returns the variance of the distribution.
+func (s SkewNormal) Variance() float64 {
+	d := s.delta()
+	return s.Sigma * s.Sigma * (1 - 2*d*d/math.Pi)
+}
+
+// stdNormalCDF evaluates the standard normal CDF.
+func stdNormalCDF(x float64) float64 {
+	return 0.5 * math.Erfc(-x/math.Sqrt2)
+}
+
+// owenT evaluates Owen's T function,
+// T(h, a) = 1/2pi * integral from 0 to a of exp(-h**2 (1+x**2)/2) / (1+x**2) dx.
+func owenT(h, a float64) float64 {
+	switch {
+	case a < 0:
+		return -owenT(h, -a)
+	case a == 0:
+		return 0
+	case math.IsInf(a, 1):
+		// T(h, inf) = (1 - Phi(|h|)) / 2
+		return 0.5 * stdNormalCDF(-math.Abs(h))
+	}
+	h = math.Abs(h)
+	if a > 1 {
+		// Reduce to a < 1 so the quadrature converges quickly.
+		ph, pah := stdNormalCDF(h), stdNormalCDF(a*h)
+		return 0.5*ph + 0.5*pah - ph*pah - owenT(a*h, 1/a)
+	}
+	// Composite Gauss-Legendre quadrature over [0, a]. The integrand is
+	// smooth, but becomes narrow for large h.
+	const pieces = 8
+	w := a / pieces
+	var s float64
+	for k := 0; k < pieces; k++ {
+		c := w * (float64(k) + 0.5)
+		for i, x := range gl16X {
+			t := c + 0.5*w*x
+			u := 1 + t*t
+			s += gl16W[i] * math.Exp(-0.5*h*h*u) / u
+		}
+	}
+	return s * 0.5 * w / (2 * math.Pi)
+}
+
+// 16-point Gauss-Legendre nodes and weights on [-1, 1].
+var (
+	gl16X = [16]float64{
+		-0.98940093499164994, -0.9445750230732326, -0.86563120238783176, -0.755404408355003,
+		-0.61787624440264377, -0.45801677765722737, -0.28160355077925892, -0.095012509837637441,
+		0.095012509837637441, 0.28160355077925892, 0.45801677765722737, 0.61787624440264377,
+		0.755404408355003, 0.86563120238783176, 0.9445750230732326, 0.98940093499164994,
+	}
+	gl16W = [16]float64{
+		0.027152459411754058, 0.062253523938647776, 0.095158511682492897, 0.12462897125553395,
+		0.14959598881657682, 0.16915651939500256, 0.18260341504492361, 0.18945061045506847,
+		0.18945061045506847, 0.18260341504492361, 0.16915651939500256, 0.14959598881657682,
+		0.12462897125553395, 0.095158511682492897, 0.062253523938647776, 0.027152459411754058,
+	}
+)
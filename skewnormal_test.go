@@ -0,0 +1,42 @@
+package crazy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSkewNormalAnalytic(t *testing.T) {
+	src := CryptoSeeded(NewMT64(), mt64N)
+	for _, shape := range []float64{-4, -0.5, 0, 1, 3, 20} {
+		d := NewSkewNormal(src, shape, 1, 2)
+		m, sd := d.Mean(), math.Sqrt(d.Variance())
+		testAnalytic(t, d, m-sd, m, m+sd, m+2*sd)
+		testMoments(t, d, 1<<16)
+	}
+}
+
+func TestSkewNormalCDF(t *testing.T) {
+	// With Shape = 1, the CDF is the square of the normal CDF.
+	d := SkewNormal{Shape: 1, Mu: 0, Sigma: 1}
+	for x := -6.0; x <= 6; x += 0.25 {
+		p := stdNormalCDF(x)
+		if c := d.CDF(x); math.Abs(c-p*p) > 1e-14 {
+			t.Errorf("CDF(%g) = %g, want %g", x, c, p*p)
+		}
+	}
+	// With Shape = 0, it is the normal CDF.
+	d.Shape = 0
+	for x := -6.0; x <= 6; x += 0.25 {
+		if c, p := d.CDF(x), stdNormalCDF(x); c != p {
+			t.Errorf("CDF(%g) = %g, want %g", x, c, p)
+		}
+	}
+}
+
+func BenchmarkSkewNormal(b *testing.B) {
+	d := NewSkewNormal(CryptoSeeded(NewMT64(), mt64N), 3, 0, 1)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = d.Next()
+	}
+}
@@ -1,6 +1,9 @@
 package crazy
 
-import "io"
+import (
+	"encoding/binary"
+	"io"
+)
 
 // A Source is a source of (pseudo) randomness.
 type Source interface {
@@ -9,6 +12,25 @@ type Source interface {
 	Read(p []byte) (n int, err error)
 }
 
+// A Uint64Source is a Source that can produce 64 bits at a time without
+// going through a byte slice. Uint64 must return the same value as reading 8
+// bytes and decoding them in little-endian order. What a shorter read does
+// with the rest of the word is up to the implementation: the PRNGs in this
+// package discard it, while BufferedRNG, and RNG wrapping one, keep it for the
+// next read.
+//
+// All PRNGs in this package implement Uint64Source, and RNG, Uniform0_1,
+// Uniform1_2, and Ziggurat use it when available, taking the low bytes of one
+// Uint64 for any value of 8 bytes or fewer. BufferedRNG is handled specially
+// so that it keeps its leftover bytes. For other sources whose short reads do
+// not discard the rest of the word, values through RNG can differ from
+// values decoded from Read.
+type Uint64Source interface {
+	Source
+	// Uint64 produces 64 random bits.
+	Uint64() uint64
+}
+
 // A Seeder is a PRNG that can be seeded. After seeding with a particular
 // value, all generators of the same type must always produce the same values.
 type Seeder interface {
@@ -51,3 +73,36 @@ type Jumper interface {
 	// least 2**64 subsequent jumps produce non-overlapping subsequences.
 	Jump()
 }
+
+// readUint returns the next n <= 8 bytes of src as a little-endian integer,
+// calling Uint64 directly when src provides it.
+func readUint(src Source, n int) uint64 {
+	switch s := src.(type) {
+	case *BufferedRNG:
+		// BufferedRNG consumes only as many bytes as requested.
+		return s.next(n)
+	case RNG:
+		return readUint(s.Source, n)
+	case Uint64Source:
+		return s.Uint64() & (^uint64(0) >> uint(64-8*n))
+	}
+	var b [8]byte
+	src.Read(b[:n])
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+// readBits reads n <= 64 random bits from src. Only a BufferedRNG keeps
+// unused bits for later. Other sources spend a whole value through readUint,
+// which is a full Uint64 for a Uint64Source.
+func readBits(src Source, n uint) uint64 {
+	switch s := src.(type) {
+	case *BufferedRNG:
+		return s.Bits(n)
+	case RNG:
+		return readBits(s.Source, n)
+	}
+	if n == 0 {
+		return 0
+	}
+	return readUint(src, int(n+7)/8) & mask(n)
+}
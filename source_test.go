@@ -0,0 +1,109 @@
+package crazy
+
+import (
+	"testing"
+)
+
+// readOnly hides every method of a Source except Read.
+type readOnly struct {
+	src Source
+}
+
+func (r readOnly) Read(p []byte) (int, error) {
+	return r.src.Read(p)
+}
+
+func TestUint64SourceConsistency(t *testing.T) {
+	gens := []func() Seeder{
+		func() Seeder { return NewXoshiro() },
+		func() Seeder { return NewMT64() },
+		func() Seeder { return NewLFG() },
+		func() Seeder { return NewXoroshiro() },
+		func() Seeder { return NewRexoroshiro() },
+	}
+	for _, g := range gens {
+		a, b := g(), g()
+		a.SeedIV([]byte("consistency"))
+		b.SeedIV([]byte("consistency"))
+		if _, ok := a.(Uint64Source); !ok {
+			t.Errorf("%T is not a Uint64Source", a)
+			continue
+		}
+		fast, slow := RNG{a}, RNG{readOnly{b}}
+		for i := 0; i < 100; i++ {
+			var x, y interface{}
+			switch i % 6 {
+			case 0:
+				x, y = fast.Uint64(), slow.Uint64()
+			case 1:
+				x, y = fast.Uint32(), slow.Uint32()
+			case 2:
+				x, y = fast.Uint16(), slow.Uint16()
+			case 3:
+				x, y = Uniform0_1{a}.Next(), Uniform0_1{slow.Source}.Next()
+			case 4:
+				x, y = Uniform1_2{a}.Next(), Uniform1_2{slow.Source}.Next()
+			case 5:
+				x, y = NewNormal(a, 0, 1).Next(), NewNormal(slow.Source, 0, 1).Next()
+			}
+			if x != y {
+				t.Fatalf("%T: value %d is %v with Uint64, %v with Read", a, i, x, y)
+			}
+		}
+	}
+}
+
+func TestRNGBuffered(t *testing.T) {
+	a, b := NewXoshiro(), NewXoshiro()
+	a.SeedIV([]byte("buffered"))
+	b.SeedIV([]byte("buffered"))
+	fast := RNG{NewBufferedRNG(a, 64)}
+	slow := RNG{readOnly{NewBufferedRNG(b, 64)}}
+	for i := 0; i < 1000; i++ {
+		var x, y uint64
+		switch i % 4 {
+		case 0:
+			x, y = fast.Uint64(), slow.Uint64()
+		case 1:
+			x, y = uint64(fast.Uint32()), uint64(slow.Uint32())
+		case 2:
+			x, y = uint64(fast.Uint16()), uint64(slow.Uint16())
+		case 3:
+			x = uint64(Uniform1_2{fast}.Next() * (1 << 52))
+			y = uint64(Uniform1_2{slow}.Next() * (1 << 52))
+		}
+		if x != y {
+			t.Fatalf("value %d is %x with fast path, %x with Read", i, x, y)
+		}
+	}
+}
+
+var sourceSink float64
+
+func BenchmarkNormalUint64Source(b *testing.B) {
+	d := NewNormal(CryptoSeeded(NewXoshiro(), 32), 0, 1)
+	for i := 0; i < b.N; i++ {
+		sourceSink += d.Next()
+	}
+}
+
+func BenchmarkNormalRead(b *testing.B) {
+	d := NewNormal(readOnly{CryptoSeeded(NewXoshiro(), 32)}, 0, 1)
+	for i := 0; i < b.N; i++ {
+		sourceSink += d.Next()
+	}
+}
+
+func BenchmarkExponentialUint64Source(b *testing.B) {
+	d := NewExponential(CryptoSeeded(NewXoshiro(), 32), 1)
+	for i := 0; i < b.N; i++ {
+		sourceSink += d.Next()
+	}
+}
+
+func BenchmarkExponentialRead(b *testing.B) {
+	d := NewExponential(readOnly{CryptoSeeded(NewXoshiro(), 32)}, 1)
+	for i := 0; i < b.N; i++ {
+		sourceSink += d.Next()
+	}
+}
@@ -0,0 +1,65 @@
+package crazy
+
+import "math"
+
+// AlphaStable adapts a Source to produce random numbers under an alpha-stable
+// distribution with stability Alpha in (0, 2], skewness Beta in [-1, 1], and
+// given Scale and location Loc, using the S1 parameterization of
+// Samorodnitsky and Taqqu. Alpha = 2 gives a normal distribution with
+// variance 2*Scale**2, and Alpha = 1, Beta = 0 gives the Cauchy distribution.
+//
+// Stable distributions generally have no closed-form density, so AlphaStable
+// does not implement Analytic.
+type AlphaStable struct {
+	Source
+	Alpha, Beta, Scale, Loc float64
+}
+
+// NewAlphaStable creates an alpha-stable distribution drawing from the
+// specified source with given parameters. It panics if alpha or beta is
+// outside its domain or if scale is not positive.
+func NewAlphaStable(src Source, alpha, beta, scale, loc float64) AlphaStable {
+	if !(alpha > 0 && alpha <= 2) {
+		panic("crazy: stable alpha not in (0, 2]")
+	}
+	if !(beta >= -1 && beta <= 1) {
+		panic("crazy: stable beta not in [-1, 1]")
+	}
+	if !(scale > 0) {
+		panic("crazy: stable scale not positive")
+	}
+	return AlphaStable{
+		Source: src,
+		Alpha:  alpha,
+		Beta:   beta,
+		Scale:  scale,
+		Loc:    loc,
+	}
+}
+
+// Next generates an alpha-stable variate using the method of Chambers,
+// Mallows, and Stuck, "A method for simulating stable random variables,"
+// JASA 71(354), 1976, as corrected by Weron.
+func (s AlphaStable) Next() float64 {
+	u := Uniform0_1{s.Source}
+	var v, w float64
+	for v == 0 {
+		v = u.Next()
+	}
+	v = math.Pi * (v - 0.5)
+	for w == 0 {
+		w = expoZig.GenNext(s.Source)
+	}
+	a, b := s.Alpha, s.Beta
+	if a == 1 {
+		pb := math.Pi/2 + b*v
+		x := (pb*math.Tan(v) - b*math.Log(math.Pi/2*w*math.Cos(v)/pb)) * 2 / math.Pi
+		return s.Scale*x + 2/math.Pi*b*s.Scale*math.Log(s.Scale) + s.Loc
+	}
+	t := b * math.Tan(math.Pi/2*a)
+	c := math.Atan(t) / a
+	d := math.Pow(1+t*t, 1/(2*a))
+	x := d * math.Sin(a*(v+c)) / math.Pow(math.Cos(v), 1/a) *
+		math.Pow(math.Cos(v-a*(v+c))/w, (1-a)/a)
+	return s.Scale*x + s.Loc
+}
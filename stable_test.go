@@ -0,0 +1,119 @@
+package crazy
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+// stableQuantiles draws n variates and returns them sorted.
+func stableQuantiles(d Distribution, n int) []float64 {
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = d.Next()
+	}
+	sort.Float64s(x)
+	return x
+}
+
+// testQuantiles checks that sample quantiles of sorted x match want. The
+// tolerance is five standard errors of the sample quantile, which is
+// sqrt(p(1-p)/n) times the slope of the quantile function.
+func testQuantiles(t *testing.T, x []float64, want func(p float64) float64) {
+	t.Helper()
+	n := float64(len(x))
+	for _, p := range []float64{0.1, 0.25, 0.5, 0.75, 0.9} {
+		q := x[int(p*n)]
+		w := want(p)
+		slope := (want(p+1e-4) - want(p-1e-4)) / 2e-4
+		if math.Abs(q-w) > 5*math.Sqrt(p*(1-p)/n)*slope {
+			t.Errorf("sample %g quantile %g, want %g", p, q, w)
+		}
+	}
+}
+
+func TestStableNormal(t *testing.T) {
+	d := NewAlphaStable(CryptoSeeded(NewMT64(), mt64N), 2, 0.5, 1, 3)
+	n := Normal{Mu: 3, Sigma: math.Sqrt2}
+	testQuantiles(t, stableQuantiles(d, 1<<18), n.Quantile)
+}
+
+func TestStableCauchy(t *testing.T) {
+	d := NewAlphaStable(CryptoSeeded(NewMT64(), mt64N), 1, 0, 2, -1)
+	testQuantiles(t, stableQuantiles(d, 1<<18), func(p float64) float64 {
+		return -1 + 2*math.Tan(math.Pi*(p-0.5))
+	})
+}
+
+func TestStableLevy(t *testing.T) {
+	d := NewAlphaStable(CryptoSeeded(NewMT64(), mt64N), 0.5, 1, 1, 0)
+	x := stableQuantiles(d, 1<<18)
+	if x[0] < 0 {
+		t.Errorf("totally skewed variate %g below location", x[0])
+	}
+	testQuantiles(t, x, func(p float64) float64 {
+		e := math.Erfcinv(p)
+		return 1 / (2 * e * e)
+	})
+}
+
+// stableCDF1 evaluates the CDF of the standard S1 stable distribution with
+// alpha = 1 and skewness beta by the Gil-Pelaez inversion of its
+// characteristic function exp(-|t| (1 + i beta (2/pi) sign(t) log|t|)),
+// substituting t = exp(s) to remove the singularity at t = 0.
+func stableCDF1(beta, x float64) float64 {
+	const h = 1e-3
+	var sum float64
+	for s := -40.0; s < 5; s += h {
+		e := math.Exp(s)
+		sum += math.Exp(-e) * math.Sin(e*x+2*beta/math.Pi*e*s)
+	}
+	return 0.5 + sum*h/math.Pi
+}
+
+func TestStableAlpha1Skewed(t *testing.T) {
+	cases := []struct{ beta, scale, loc float64 }{
+		{0.8, 1, 0},
+		{-0.8, 2, 1},
+		{1, 0.5, -3},
+	}
+	for _, c := range cases {
+		d := NewAlphaStable(CryptoSeeded(NewMT64(), mt64N), 1, c.beta, c.scale, c.loc)
+		x := stableQuantiles(d, 1<<18)
+		n := float64(len(x))
+		// In S1, X = Scale*Z + (2/pi) Beta Scale log(Scale) + Loc for standard Z.
+		shift := 2/math.Pi*c.beta*c.scale*math.Log(c.scale) + c.loc
+		for _, p := range []float64{0.1, 0.25, 0.5, 0.75, 0.9} {
+			q := x[int(p*n)]
+			if f := stableCDF1(c.beta, (q-shift)/c.scale); math.Abs(f-p) > 5*math.Sqrt(p*(1-p)/n) {
+				t.Errorf("beta %g: CDF at sample %g quantile %g is %g", c.beta, p, q, f)
+			}
+		}
+	}
+}
+
+func TestStableScalePanics(t *testing.T) {
+	cases := map[string]func(){
+		"AlphaStable": func() { NewAlphaStable(nil, 1.5, 0, 0, 0) },
+		"GEV":         func() { NewGEV(nil, 0, 0, -1) },
+		"SkewNormal":  func() { NewSkewNormal(nil, 1, 0, math.NaN()) },
+	}
+	for name, f := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s with non-positive scale did not panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func BenchmarkAlphaStable(b *testing.B) {
+	d := NewAlphaStable(CryptoSeeded(NewMT64(), mt64N), 1.5, 0.5, 1, 0)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = d.Next()
+	}
+}
@@ -0,0 +1,78 @@
+package crazy
+
+import "math/bits"
+
+// Uint128 generates a random 128-bit integer as its high and low halves. The
+// low half is drawn first, so the 16 bytes consumed are the little-endian
+// encoding of the result.
+func (r RNG) Uint128() (hi, lo uint64) {
+	lo = r.Uint64()
+	hi = r.Uint64()
+	return hi, lo
+}
+
+// Uint128n generates a random 128-bit integer in the interval [0, max), where
+// max is given as its high and low halves. Like Uint64n, it uses Lemire's
+// multiply-shift method, taking the high half of the 256-bit product of a
+// random 128-bit value and max. It panics if max == 0.
+func (r RNG) Uint128n(maxHi, maxLo uint64) (hi, lo uint64) {
+	if maxHi == 0 && maxLo == 0 {
+		panic("maximum zero")
+	}
+	xh, xl := r.Uint128()
+	p3, p2, p1, p0 := Mul128(xh, xl, maxHi, maxLo)
+	if less128(p1, p0, maxHi, maxLo) {
+		nl, b := bits.Sub64(0, maxLo, 0)
+		nh, _ := bits.Sub64(0, maxHi, b)
+		th, tl := rem128(nh, nl, maxHi, maxLo)
+		for less128(p1, p0, th, tl) && !failed(r.Source) {
+			xh, xl = r.Uint128()
+			p3, p2, p1, p0 = Mul128(xh, xl, maxHi, maxLo)
+		}
+	}
+	return p3, p2
+}
+
+// Mul128 returns the 256-bit product of the 128-bit integers x and y, each
+// given as its high and low halves. The product is returned as four words from
+// most to least significant.
+func Mul128(xHi, xLo, yHi, yLo uint64) (p3, p2, p1, p0 uint64) {
+	h00, l00 := bits.Mul64(xLo, yLo)
+	h01, l01 := bits.Mul64(xLo, yHi)
+	h10, l10 := bits.Mul64(xHi, yLo)
+	h11, l11 := bits.Mul64(xHi, yHi)
+	var c1, c2, c3 uint64
+	p0 = l00
+	p1, c1 = bits.Add64(h00, l01, 0)
+	p1, c2 = bits.Add64(p1, l10, 0)
+	p2, c3 = bits.Add64(h01, h10, c1)
+	p3 = h11 + c3
+	p2, c1 = bits.Add64(p2, l11, c2)
+	p3 += c1
+	return p3, p2, p1, p0
+}
+
+// less128 reports whether the 128-bit integer (ah, al) is less than (bh, bl).
+func less128(ah, al, bh, bl uint64) bool {
+	return ah < bh || ah == bh && al < bl
+}
+
+// rem128 returns the remainder of the 128-bit integer (nh, nl) divided by the
+// nonzero (mh, ml).
+func rem128(nh, nl, mh, ml uint64) (uint64, uint64) {
+	if mh == 0 {
+		return 0, bits.Rem64(nh, nl, ml)
+	}
+	// The quotient fits in 64 bits, so binary long division takes at most 64
+	// steps.
+	for s := bits.LeadingZeros64(mh) - bits.LeadingZeros64(nh); s >= 0; s-- {
+		dh := mh<<uint(s) | ml>>(64-uint(s))
+		dl := ml << uint(s)
+		if !less128(nh, nl, dh, dl) {
+			var b uint64
+			nl, b = bits.Sub64(nl, dl, 0)
+			nh, _ = bits.Sub64(nh, dh, b)
+		}
+	}
+	return nh, nl
+}
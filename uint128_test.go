@@ -0,0 +1,150 @@
+package crazy
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+// int128 converts the halves of a 128-bit integer to a big.Int.
+func int128(hi, lo uint64) *big.Int {
+	z := new(big.Int).SetUint64(hi)
+	z.Lsh(z, 64)
+	return z.Or(z, new(big.Int).SetUint64(lo))
+}
+
+func TestMul128(t *testing.T) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	const max = ^uint64(0)
+	cases := [][4]uint64{
+		{0, 0, 0, 0},
+		{0, 1, 0, 1},
+		{max, max, max, max},
+		{max, max, 0, 1},
+		{1, 0, 1, 0},
+		{0, max, 0, max},
+		{max, 0, max, 0},
+	}
+	for i := 0; i < 1000; i++ {
+		cases = append(cases, [4]uint64{r.Uint64(), r.Uint64(), r.Uint64(), r.Uint64()})
+	}
+	for _, c := range cases {
+		p3, p2, p1, p0 := Mul128(c[0], c[1], c[2], c[3])
+		got := int128(p3, p2)
+		got.Lsh(got, 128)
+		got.Or(got, int128(p1, p0))
+		want := new(big.Int).Mul(int128(c[0], c[1]), int128(c[2], c[3]))
+		if got.Cmp(want) != 0 {
+			t.Fatalf("Mul128(%#x, %#x, %#x, %#x) = %#x, want %#x", c[0], c[1], c[2], c[3], got, want)
+		}
+	}
+}
+
+func TestRem128(t *testing.T) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	for i := 0; i < 10000; i++ {
+		nh, nl := r.Uint128()
+		mh, ml := r.Uint64()>>(i%64), r.Uint64()
+		switch i % 4 {
+		case 0:
+			mh = 0
+		case 1:
+			nh >>= i % 64
+		}
+		if mh == 0 && ml == 0 {
+			ml = 1
+		}
+		rh, rl := rem128(nh, nl, mh, ml)
+		want := new(big.Int).Rem(int128(nh, nl), int128(mh, ml))
+		if got := int128(rh, rl); got.Cmp(want) != 0 {
+			t.Fatalf("rem128(%#x, %#x, %#x, %#x) = %#x, want %#x", nh, nl, mh, ml, got, want)
+		}
+	}
+}
+
+func TestUint128(t *testing.T) {
+	a, b := NewXoshiro(), NewXoshiro()
+	a.Seed(128)
+	b.Seed(128)
+	hi, lo := RNG{a}.Uint128()
+	if x := b.Uint64(); x != lo {
+		t.Errorf("low half is %#x, want first word %#x", lo, x)
+	}
+	if x := b.Uint64(); x != hi {
+		t.Errorf("high half is %#x, want second word %#x", hi, x)
+	}
+}
+
+func TestUint128n(t *testing.T) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	const n, k = 70000, 7
+	cases := []struct {
+		name         string
+		maxHi, maxLo uint64
+		// bucket maps a result to one of k equally likely buckets.
+		bucket func(hi, lo uint64) int
+	}{
+		{"small", 0, k, func(hi, lo uint64) int { return int(lo) }},
+		{"high", k, 0, func(hi, lo uint64) int { return int(hi) }},
+		{"full", ^uint64(0), ^uint64(0), func(hi, lo uint64) int { return int(hi % k) }},
+	}
+	for _, c := range cases {
+		counts := make([]int, k)
+		for i := 0; i < n; i++ {
+			hi, lo := r.Uint128n(c.maxHi, c.maxLo)
+			if !less128(hi, lo, c.maxHi, c.maxLo) {
+				t.Fatalf("%s: Uint128n gave %#x %#x", c.name, hi, lo)
+			}
+			counts[c.bucket(hi, lo)]++
+		}
+		chi2 := chi2Uniform(counts, k)
+		if chi2 > 28 {
+			t.Errorf("%s: Uint128n is not uniform: chi2 = %v, counts %v", c.name, chi2, counts)
+		}
+	}
+}
+
+func TestUint128nLarge(t *testing.T) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	// With max just above 2**127, nearly half of all 128-bit values are in the
+	// biased region, so rejection is exercised constantly.
+	mh, ml := uint64(1)<<63, uint64(1)
+	var high int
+	const n = 100000
+	for i := 0; i < n; i++ {
+		hi, lo := r.Uint128n(mh, ml)
+		if !less128(hi, lo, mh, ml) {
+			t.Fatalf("Uint128n gave %#x %#x", hi, lo)
+		}
+		if hi >= 1<<62 {
+			high++
+		}
+	}
+	if d := float64(high) - n/2; math.Abs(d) > 5*math.Sqrt(n/4) {
+		t.Errorf("%d of %d values in upper half", high, n)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Uint128n(0) did not panic")
+		}
+	}()
+	r.Uint128n(0, 0)
+}
+
+var uint128Sink uint64
+
+func BenchmarkUint128n(b *testing.B) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	for i := 0; i < b.N; i++ {
+		hi, lo := r.Uint128n(12345, 67890)
+		uint128Sink += hi ^ lo
+	}
+}
+
+func BenchmarkUint128nBign(b *testing.B) {
+	r := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	max := int128(12345, 67890)
+	for i := 0; i < b.N; i++ {
+		uint128Sink += r.Bign(max).Uint64()
+	}
+}
@@ -156,36 +156,51 @@ type Swapper interface {
 	Len() int
 }
 
-// Shuffle permutes the elements of data into a random order.
+// Shuffle permutes the elements of data into a uniformly random order using
+// the Fisher-Yates algorithm.
 func Shuffle(data Swapper, rng RNG) {
-	n := data.Len()
-	for i := 0; i < n; i++ {
-		data.Swap(i, int(rng.Uintn(uint(n))))
+	for i := data.Len() - 1; i > 0; i-- {
+		data.Swap(i, rng.Intn(i+1))
 	}
 }
 
-// ShuffleFloat64s permutes a slice of float64s into a random order.
-func ShuffleFloat64s(data []float64, rng RNG) {
-	for i := range data {
-		k := rng.Intn(len(data))
+// ShuffleSlice permutes the elements of data into a uniformly random order
+// using the Fisher-Yates algorithm.
+func ShuffleSlice[T any](data []T, rng RNG) {
+	for i := len(data) - 1; i > 0; i-- {
+		k := rng.Intn(i + 1)
 		data[i], data[k] = data[k], data[i]
 	}
 }
 
+// PartialShuffle randomizes the first k elements of data, such that they are
+// a uniformly random selection of k elements of data in uniformly random
+// order. The remaining elements are left in an unspecified order. This costs
+// O(k) rather than the O(len(data)) of a full shuffle. It panics if k is
+// negative or greater than len(data).
+func PartialShuffle[T any](data []T, k int, rng RNG) {
+	if k < 0 || k > len(data) {
+		panic("crazy: partial shuffle length out of range")
+	}
+	for i := 0; i < k; i++ {
+		j := i + rng.Intn(len(data)-i)
+		data[i], data[j] = data[j], data[i]
+	}
+}
+
+// ShuffleFloat64s permutes a slice of float64s into a random order.
+func ShuffleFloat64s(data []float64, rng RNG) {
+	ShuffleSlice(data, rng)
+}
+
 // ShuffleInts permutes a slice of ints into a random order.
 func ShuffleInts(data []int, rng RNG) {
-	for i := range data {
-		k := rng.Intn(len(data))
-		data[i], data[k] = data[k], data[i]
-	}
+	ShuffleSlice(data, rng)
 }
 
 // ShuffleStrings permutes a slice of strings into a random order.
 func ShuffleStrings(data []string, rng RNG) {
-	for i := range data {
-		k := rng.Intn(len(data))
-		data[i], data[k] = data[k], data[i]
-	}
+	ShuffleSlice(data, rng)
 }
 
 // Yield sends values generated from the given distribution. It stops and
@@ -230,6 +245,19 @@ func SeedInt64(src Seeder, seed int64) {
 	src.SeedIV(b[:])
 }
 
+// Streams produces n copies of src, each jumped once more than the last, for
+// use as non-overlapping streams by parallel random processes. src itself is
+// jumped n times. It panics if src does not also implement Copier.
+func Streams(src Jumper, n int) []Jumper {
+	c := src.(Copier)
+	s := make([]Jumper, n)
+	for i := range s {
+		s[i] = c.Copy().(Jumper)
+		src.Jump()
+	}
+	return s
+}
+
 // CryptoSeeded seeds a Seeder with n random bytes from crypto/rand.Reader and
 // returns src.
 func CryptoSeeded(src Seeder, n int) Seeder {
@@ -1,6 +1,10 @@
 package crazy
 
-import "testing"
+import (
+	"fmt"
+	"sort"
+	"testing"
+)
 
 func BenchmarkYield(b *testing.B) {
 	d := Uniform0_1{CryptoSeeded(NewMT64(), mt64N)}
@@ -23,3 +27,130 @@ func BenchmarkYieldUint64(b *testing.B) {
 		}
 	})
 }
+
+func TestStreams(t *testing.T) {
+	src := CryptoSeeded(NewXoshiro(), 32).(*Xoshiro)
+	cp := *src
+	s := Streams(src, 4)
+	for i, v := range s {
+		x := *v.(*Xoshiro)
+		if x != cp {
+			t.Errorf("stream %d not at expected state", i)
+		}
+		cp.Jump()
+	}
+	if *src != cp {
+		t.Error("source not jumped past streams")
+	}
+}
+
+// chi2 computes the chi-squared statistic of observed counts against expected
+// counts.
+func chi2[K comparable](obs map[K]int, want map[K]float64) float64 {
+	var s float64
+	for k, e := range want {
+		d := float64(obs[k]) - e
+		s += d * d / e
+	}
+	return s
+}
+
+// chi2Uniform computes the chi-squared statistic of counts against equal
+// expected counts in each of k cells. Cells beyond len(counts) count as zero.
+func chi2Uniform(counts []int, k int) float64 {
+	var n int
+	for _, c := range counts {
+		n += c
+	}
+	e := float64(n) / float64(k)
+	s := float64(k-len(counts)) * e
+	for _, c := range counts {
+		d := float64(c) - e
+		s += d * d / e
+	}
+	return s
+}
+
+// mapCounts returns the counts in m in an arbitrary order.
+func mapCounts[K comparable](m map[K]int) []int {
+	c := make([]int, 0, len(m))
+	for _, v := range m {
+		c = append(c, v)
+	}
+	return c
+}
+
+// testPermutations checks with a chi-squared test that shuffle produces each
+// ordering of four elements equally often.
+func testPermutations(t *testing.T, name string, shuffle func([]int)) {
+	t.Helper()
+	const n = 24000
+	counts := make(map[string]int)
+	p := make([]int, 4)
+	for i := 0; i < n; i++ {
+		for j := range p {
+			p[j] = j
+		}
+		shuffle(p)
+		counts[fmt.Sprint(p)]++
+	}
+	if len(counts) != 24 {
+		t.Errorf("%s produced %d permutations, want 24", name, len(counts))
+	}
+	chi2 := chi2Uniform(mapCounts(counts), 24)
+	// The 99.99th percentile of chi-squared with 23 degrees of freedom is
+	// about 56.
+	if chi2 > 60 {
+		t.Errorf("%s permutations are not uniform: chi2 = %v", name, chi2)
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	rng := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	testPermutations(t, "Shuffle", func(p []int) { Shuffle(sort.IntSlice(p), rng) })
+	testPermutations(t, "ShuffleSlice", func(p []int) { ShuffleSlice(p, rng) })
+	testPermutations(t, "ShuffleInts", func(p []int) { ShuffleInts(p, rng) })
+	testPermutations(t, "PartialShuffle", func(p []int) { PartialShuffle(p, 3, rng) })
+}
+
+func TestPartialShuffle(t *testing.T) {
+	rng := RNG{CryptoSeeded(NewXoshiro(), 32)}
+	const n = 12000
+	// The first two of six elements should be each of the 30 ordered pairs
+	// equally often.
+	counts := make(map[[2]int]int)
+	p := make([]int, 6)
+	for i := 0; i < n; i++ {
+		for j := range p {
+			p[j] = j
+		}
+		PartialShuffle(p, 2, rng)
+		counts[[2]int{p[0], p[1]}]++
+		q := append([]int(nil), p...)
+		sort.Ints(q)
+		for j, v := range q {
+			if v != j {
+				t.Fatalf("partial shuffle is not a permutation: %v", p)
+			}
+		}
+	}
+	if len(counts) != 30 {
+		t.Errorf("got %d ordered pairs, want 30", len(counts))
+	}
+	chi2 := chi2Uniform(mapCounts(counts), 30)
+	// The 99.99th percentile of chi-squared with 29 degrees of freedom is
+	// about 66.
+	if chi2 > 70 {
+		t.Errorf("pairs are not uniform: chi2 = %v", chi2)
+	}
+	for _, k := range []int{-1, 7} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("no panic with k = %d", k)
+				}
+			}()
+			PartialShuffle(p, k, rng)
+		}()
+	}
+}
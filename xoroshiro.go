@@ -95,7 +95,7 @@ func (xoro *Xoroshiro) Uint64() uint64 {
 // unused bytes. n will always be len(p) and err will always be nil.
 func (xoro *Xoroshiro) Read(p []byte) (n int, err error) {
 	n = len(p)
-	for len(p) >= 8 {
+	for len(p) > 8 {
 		binary.LittleEndian.PutUint64(p, xoro.Uint64())
 		p = p[8:]
 	}
@@ -1,5 +1,7 @@
 package crazy
 
+import "math"
+
 // Ziggurat implements a generalized ziggurat algorithm for producing random
 // values according to any monotone decreasing density, optionally symmetric
 // about zero. See http://www.jstatsoft.org/v05/i08/paper.
@@ -28,6 +30,9 @@ type Ziggurat struct {
 func (z *Ziggurat) GenNext(src Source) float64 {
 	for {
 		j := int64(RNG{src}.Uint64())
+		if failed(src) {
+			return math.NaN()
+		}
 		i := uint16(j >> 54 & 0x3ff)
 		if z.Mirrored {
 			j = j << 10 >> 10